@@ -0,0 +1,58 @@
+package main
+
+import (
+	"github.com/almasx/pokedexcli/internal/cli"
+	"github.com/almasx/pokedexcli/internal/types"
+)
+
+// areaNames offers the location areas listed by map, and the current one.
+func areaNames(config *cli.Config) []string {
+	names := make([]string, 0, len(config.Areas)+1)
+	for name := range config.Areas {
+		names = append(names, name)
+	}
+	if config.Location != "" {
+		names = append(names, config.Location)
+	}
+	return names
+}
+
+// ownedNames offers every owned pokemon by nickname and species.
+func ownedNames(config *cli.Config) []string {
+	var names []string
+	if config.Collection != nil {
+		for _, owned := range config.Collection.All() {
+			names = append(names, owned.Name(), owned.Pokemon.Name)
+		}
+	}
+	return names
+}
+
+// dexNames offers every caught or owned pokemon, for inspect.
+func dexNames(config *cli.Config) []string {
+	names := ownedNames(config)
+	for name := range config.Pokedex {
+		names = append(names, name)
+	}
+	return names
+}
+
+// speciesNames offers every species seen or caught so far.
+func speciesNames(config *cli.Config) []string {
+	names := dexNames(config)
+	for name := range config.Seen {
+		names = append(names, name)
+	}
+	return names
+}
+
+// typeOrSpeciesNames offers the attacking side of matchup.
+func typeOrSpeciesNames(config *cli.Config) []string {
+	return append(speciesNames(config), types.Names...)
+}
+
+func fixed(values ...string) func(*cli.Config) []string {
+	return func(*cli.Config) []string {
+		return values
+	}
+}
@@ -1,3 +1,10 @@
 module github.com/almasx/pokedexcli
 
 go 1.23.4
+
+require github.com/peterh/liner v1.2.2
+
+require (
+	github.com/mattn/go-runewidth v0.0.3 // indirect
+	golang.org/x/sys v0.27.0 // indirect
+)
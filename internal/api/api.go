@@ -346,4 +346,180 @@ type GetLocationAreaPokemons struct {
 			} `json:"encounter_details"`
 		} `json:"version_details"`
 	} `json:"pokemon_encounters"`
-}
\ No newline at end of file
+}
+type GetPokemonSpecies struct {
+	ID                   int    `json:"id"`
+	Name                 string `json:"name"`
+	Order                int    `json:"order"`
+	GenderRate           int    `json:"gender_rate"`
+	CaptureRate          int    `json:"capture_rate"`
+	BaseHappiness        int    `json:"base_happiness"`
+	IsBaby               bool   `json:"is_baby"`
+	IsLegendary          bool   `json:"is_legendary"`
+	IsMythical           bool   `json:"is_mythical"`
+	HatchCounter         int    `json:"hatch_counter"`
+	HasGenderDifferences bool   `json:"has_gender_differences"`
+	FormsSwitchable      bool   `json:"forms_switchable"`
+	GrowthRate           struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"growth_rate"`
+	EvolvesFromSpecies *struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"evolves_from_species"`
+	EvolutionChain struct {
+		URL string `json:"url"`
+	} `json:"evolution_chain"`
+	Generation struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"generation"`
+	Habitat *struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"habitat"`
+	Names []struct {
+		Name     string `json:"name"`
+		Language struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"language"`
+	} `json:"names"`
+	PokedexNumbers []struct {
+		EntryNumber int `json:"entry_number"`
+		Pokedex     struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"pokedex"`
+	} `json:"pokedex_numbers"`
+	Varieties []struct {
+		IsDefault bool `json:"is_default"`
+		Pokemon   struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"pokemon"`
+	} `json:"varieties"`
+}
+
+type NamedResource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type EvolutionDetail struct {
+	Item                  *NamedResource `json:"item"`
+	Trigger               NamedResource  `json:"trigger"`
+	Gender                *int           `json:"gender"`
+	HeldItem              *NamedResource `json:"held_item"`
+	KnownMove             *NamedResource `json:"known_move"`
+	KnownMoveType         *NamedResource `json:"known_move_type"`
+	Location              *NamedResource `json:"location"`
+	MinLevel              *int           `json:"min_level"`
+	MinHappiness          *int           `json:"min_happiness"`
+	MinBeauty             *int           `json:"min_beauty"`
+	MinAffection          *int           `json:"min_affection"`
+	NeedsOverworldRain    bool           `json:"needs_overworld_rain"`
+	PartySpecies          *NamedResource `json:"party_species"`
+	PartyType             *NamedResource `json:"party_type"`
+	RelativePhysicalStats *int           `json:"relative_physical_stats"`
+	TimeOfDay             string         `json:"time_of_day"`
+	TradeSpecies          *NamedResource `json:"trade_species"`
+	TurnUpsideDown        bool           `json:"turn_upside_down"`
+}
+
+type ChainLink struct {
+	IsBaby           bool              `json:"is_baby"`
+	Species          NamedResource     `json:"species"`
+	EvolutionDetails []EvolutionDetail `json:"evolution_details"`
+	EvolvesTo        []ChainLink       `json:"evolves_to"`
+}
+
+type GetEvolutionChain struct {
+	ID              int            `json:"id"`
+	BabyTriggerItem *NamedResource `json:"baby_trigger_item"`
+	Chain           ChainLink      `json:"chain"`
+}
+
+type GetType struct {
+	ID              int    `json:"id"`
+	Name            string `json:"name"`
+	DamageRelations struct {
+		NoDamageTo       []NamedResource `json:"no_damage_to"`
+		HalfDamageTo     []NamedResource `json:"half_damage_to"`
+		DoubleDamageTo   []NamedResource `json:"double_damage_to"`
+		NoDamageFrom     []NamedResource `json:"no_damage_from"`
+		HalfDamageFrom   []NamedResource `json:"half_damage_from"`
+		DoubleDamageFrom []NamedResource `json:"double_damage_from"`
+	} `json:"damage_relations"`
+	Generation NamedResource `json:"generation"`
+	Pokemon    []struct {
+		Slot    int           `json:"slot"`
+		Pokemon NamedResource `json:"pokemon"`
+	} `json:"pokemon"`
+	Moves []NamedResource `json:"moves"`
+}
+
+type GetMove struct {
+	ID           int           `json:"id"`
+	Name         string        `json:"name"`
+	Accuracy     *int          `json:"accuracy"`
+	EffectChance *int          `json:"effect_chance"`
+	PP           int           `json:"pp"`
+	Priority     int           `json:"priority"`
+	Power        *int          `json:"power"`
+	DamageClass  NamedResource `json:"damage_class"`
+	Type         NamedResource `json:"type"`
+	Target       NamedResource `json:"target"`
+	Generation   NamedResource `json:"generation"`
+}
+
+type GetNamedList struct {
+	Count    int             `json:"count"`
+	Next     string          `json:"next"`
+	Previous string          `json:"previous"`
+	Results  []NamedResource `json:"results"`
+}
+
+type GetGeneration struct {
+	ID             int             `json:"id"`
+	Name           string          `json:"name"`
+	MainRegion     NamedResource   `json:"main_region"`
+	PokemonSpecies []NamedResource `json:"pokemon_species"`
+	VersionGroups  []NamedResource `json:"version_groups"`
+}
+
+type GetRegion struct {
+	ID             int             `json:"id"`
+	Name           string          `json:"name"`
+	Locations      []NamedResource `json:"locations"`
+	MainGeneration NamedResource   `json:"main_generation"`
+	Pokedexes      []NamedResource `json:"pokedexes"`
+	VersionGroups  []NamedResource `json:"version_groups"`
+}
+
+type GetPokedex struct {
+	ID             int    `json:"id"`
+	Name           string `json:"name"`
+	IsMainSeries   bool   `json:"is_main_series"`
+	PokemonEntries []struct {
+		EntryNumber    int           `json:"entry_number"`
+		PokemonSpecies NamedResource `json:"pokemon_species"`
+	} `json:"pokemon_entries"`
+	Region *NamedResource `json:"region"`
+}
+
+type GetLocation struct {
+	ID     int             `json:"id"`
+	Name   string          `json:"name"`
+	Region *NamedResource  `json:"region"`
+	Areas  []NamedResource `json:"areas"`
+	Names  []struct {
+		Name     string        `json:"name"`
+		Language NamedResource `json:"language"`
+	} `json:"names"`
+	GameIndices []struct {
+		GameIndex  int           `json:"game_index"`
+		Generation NamedResource `json:"generation"`
+	} `json:"game_indices"`
+}
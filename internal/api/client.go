@@ -0,0 +1,261 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/almasx/pokedexcli/internal/pokecache"
+)
+
+const (
+	DefaultBaseURL = "https://pokeapi.co/api/v2"
+	DefaultTimeout = 30 * time.Second
+)
+
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	Cache      *pokecache.Cache
+	Mode       Mode
+	DataDir    string
+	// Timeout bounds each HTTP request; zero means no limit beyond the
+	// caller's context.
+	Timeout time.Duration
+	Retry   RetryPolicy
+	// Limiter spaces out network requests; nil means no limit.
+	Limiter *Limiter
+
+	flight   flight
+	policies []CachePolicy
+	// refreshes tracks background refreshes of stale entries.
+	refreshes sync.WaitGroup
+}
+
+func NewClient(cache *pokecache.Cache) *Client {
+	c := &Client{
+		BaseURL:    DefaultBaseURL,
+		HTTPClient: http.DefaultClient,
+		Cache:      cache,
+		Timeout:    DefaultTimeout,
+		Retry:      DefaultRetryPolicy,
+		Limiter:    NewLimiter(DefaultRequestsPerSecond),
+	}
+	c.SetCachePolicies(DefaultCachePolicies)
+	return c
+}
+
+// URL resolves an API path such as "pokemon/pikachu" against the base URL.
+// Absolute URLs (e.g. pagination links from the API) are returned unchanged.
+func (c *Client) URL(path string) string {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	return strings.TrimRight(c.BaseURL, "/") + "/" + strings.TrimLeft(path, "/")
+}
+
+// Fetch returns the raw body for rawURL, serving it from the cache when
+// possible. Stale entries are returned at once and refreshed in the
+// background; expired ones with an ETag are revalidated before use, or
+// served as they are when the API cannot be reached. Concurrent fetches of
+// the same URL share one request.
+func (c *Client) Fetch(ctx context.Context, rawURL string) ([]byte, error) {
+	var cached pokecache.Entry
+	if c.Cache != nil {
+		var freshness pokecache.Freshness
+		cached, freshness = c.Cache.Lookup(rawURL)
+		switch freshness {
+		case pokecache.Fresh:
+			return cached.Val, nil
+		case pokecache.Stale:
+			c.refresh(rawURL, cached)
+			return cached.Val, nil
+		}
+	}
+	// The fetch is shared with other callers, so it keeps this caller's
+	// values but not its cancellation; Timeout and Retry still bound it.
+	shared := context.WithoutCancel(ctx)
+	return c.flight.do(ctx, rawURL, func() ([]byte, error) {
+		return c.fill(shared, rawURL, cached)
+	})
+}
+
+// refresh fetches a new copy of a stale entry without blocking the caller.
+// It is not tied to any command, so interrupting one does not cancel it.
+func (c *Client) refresh(rawURL string, cached pokecache.Entry) {
+	c.refreshes.Add(1)
+	go func() {
+		defer c.refreshes.Done()
+		c.flight.do(context.Background(), rawURL, func() ([]byte, error) {
+			return c.fill(context.Background(), rawURL, cached)
+		})
+	}()
+}
+
+// Wait blocks until background refreshes have finished.
+func (c *Client) Wait() {
+	c.refreshes.Wait()
+}
+
+// fill fetches rawURL from its source and stores it in the cache. A cached
+// entry with an ETag is revalidated rather than downloaded again.
+func (c *Client) fill(ctx context.Context, rawURL string, cached pokecache.Entry) ([]byte, error) {
+	// Another flight may have filled the cache between our miss and now.
+	if c.Cache != nil {
+		if entry, freshness := c.Cache.Lookup(rawURL); freshness == pokecache.Fresh {
+			return entry.Val, nil
+		}
+	}
+
+	var resp response
+	var err error
+	if c.Mode == ModeOffline {
+		resp.body, err = c.readFixture(rawURL)
+	} else {
+		resp, err = c.get(ctx, rawURL, cached.ETag)
+		// An expired entry beats no answer when the API cannot be reached.
+		if err != nil && cached.Val != nil && unreachable(err) {
+			return cached.Val, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp.notModified {
+		if c.Cache != nil {
+			c.Cache.Revalidated(rawURL, cached)
+		}
+		return cached.Val, nil
+	}
+	if c.Mode == ModeRecord {
+		if err := c.writeFixture(rawURL, resp.body); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.Cache != nil {
+		c.Cache.AddEntry(rawURL, pokecache.Entry{Val: resp.body, ETag: resp.etag})
+	}
+	return resp.body, nil
+}
+
+// unreachable reports whether err means the API could not give an answer,
+// because of the network or a server error, rather than a real response
+// such as not found.
+func unreachable(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var status *StatusError
+	if errors.As(err, &status) {
+		return status.StatusCode >= 500
+	}
+	return true
+}
+
+// Get fetches path and decodes the JSON response into a T.
+func Get[T any](ctx context.Context, c *Client, path string) (T, error) {
+	var res T
+	body, err := c.Fetch(ctx, c.URL(path))
+	if err != nil {
+		return res, err
+	}
+	if err := json.Unmarshal(body, &res); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// LocationAreas returns a page of location areas. page is a pagination URL
+// as returned in Next/Previous; an empty page fetches the first one.
+func (c *Client) LocationAreas(ctx context.Context, page string) (GetLocationAreas, error) {
+	if page == "" {
+		page = "location-area/?offset=0&limit=20"
+	}
+	return Get[GetLocationAreas](ctx, c, page)
+}
+
+func (c *Client) LocationArea(ctx context.Context, name string) (GetLocationAreaPokemons, error) {
+	return Get[GetLocationAreaPokemons](ctx, c, "location-area/"+url.PathEscape(name))
+}
+
+func (c *Client) Pokemon(ctx context.Context, name string) (GetPokemon, error) {
+	return Get[GetPokemon](ctx, c, "pokemon/"+url.PathEscape(name))
+}
+
+func (c *Client) PokemonSpecies(ctx context.Context, name string) (GetPokemonSpecies, error) {
+	return Get[GetPokemonSpecies](ctx, c, "pokemon-species/"+url.PathEscape(name))
+}
+
+// EvolutionChain accepts a chain ID or the URL from GetPokemonSpecies.EvolutionChain.
+func (c *Client) EvolutionChain(ctx context.Context, ref string) (GetEvolutionChain, error) {
+	if !strings.Contains(ref, "/") {
+		ref = "evolution-chain/" + url.PathEscape(ref)
+	}
+	return Get[GetEvolutionChain](ctx, c, ref)
+}
+
+func (c *Client) Type(ctx context.Context, name string) (GetType, error) {
+	return Get[GetType](ctx, c, "type/"+url.PathEscape(name))
+}
+
+func (c *Client) Move(ctx context.Context, name string) (GetMove, error) {
+	return Get[GetMove](ctx, c, "move/"+url.PathEscape(name))
+}
+
+// ResourceID extracts the numeric ID from a resource URL such as
+// "https://pokeapi.co/api/v2/pokemon-species/25/", or 0 if there is none.
+func ResourceID(resourceURL string) int {
+	parts := strings.Split(strings.TrimRight(resourceURL, "/"), "/")
+	id, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+// SpeciesID is the national dex number of the pokemon's species. Alternate
+// forms have pokemon IDs above 10000 but share their species' number.
+func (p GetPokemon) SpeciesID() int {
+	if id := ResourceID(p.Species.URL); id != 0 {
+		return id
+	}
+	return p.ID
+}
+
+// IsForm reports whether a pokemon resource URL is an alternate form, whose
+// ID is not a national dex number.
+func IsForm(pokemonURL string) bool {
+	return ResourceID(pokemonURL) > 10000
+}
+
+// List fetches every entry of a named resource list such as "generation".
+func (c *Client) List(ctx context.Context, resource string) ([]NamedResource, error) {
+	list, err := Get[GetNamedList](ctx, c, resource+"?limit=100000&offset=0")
+	if err != nil {
+		return nil, err
+	}
+	return list.Results, nil
+}
+
+func (c *Client) Generation(ctx context.Context, name string) (GetGeneration, error) {
+	return Get[GetGeneration](ctx, c, "generation/"+url.PathEscape(name))
+}
+
+func (c *Client) Region(ctx context.Context, name string) (GetRegion, error) {
+	return Get[GetRegion](ctx, c, "region/"+url.PathEscape(name))
+}
+
+func (c *Client) Pokedex(ctx context.Context, name string) (GetPokedex, error) {
+	return Get[GetPokedex](ctx, c, "pokedex/"+url.PathEscape(name))
+}
+
+func (c *Client) Location(ctx context.Context, name string) (GetLocation, error) {
+	return Get[GetLocation](ctx, c, "location/"+url.PathEscape(name))
+}
@@ -0,0 +1,197 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/almasx/pokedexcli/internal/api"
+	"github.com/almasx/pokedexcli/internal/apitest"
+	"github.com/almasx/pokedexcli/internal/pokecache"
+)
+
+func TestClientGet(t *testing.T) {
+	ctx := context.Background()
+	requests := 0
+	client := apitest.NewClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/pokemon/pikachu":
+			w.Write([]byte(`{"id": 25, "name": "pikachu", "base_experience": 112}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	for i := 0; i < 2; i++ {
+		pokemon, err := client.Pokemon(ctx, "pikachu")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if pokemon.ID != 25 || pokemon.Name != "pikachu" {
+			t.Errorf("expected pikachu #25, got %v #%v", pokemon.Name, pokemon.ID)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %v", requests)
+	}
+
+	if _, err := client.Pokemon(ctx, "missingno"); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("expected not found for 404 response, got %v", err)
+	}
+	if _, ok := client.Cache.Get(client.URL("pokemon/missingno")); ok {
+		t.Errorf("expected 404 response not to be cached")
+	}
+}
+
+func TestClientOffline(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"count": 1, "results": [{"name": "canalave-city-area"}]}`))
+	}))
+
+	dir := t.TempDir()
+	recorder := api.NewClient(nil)
+	recorder.BaseURL = server.URL + "/api/v2"
+	recorder.Mode = api.ModeRecord
+	recorder.DataDir = dir
+	if _, err := recorder.LocationAreas(ctx, ""); err != nil {
+		t.Fatalf("unexpected error while recording: %v", err)
+	}
+	server.Close()
+
+	offline := api.NewClient(nil)
+	offline.BaseURL = server.URL + "/api/v2"
+	offline.Mode = api.ModeOffline
+	offline.DataDir = dir
+	areas, err := offline.LocationAreas(ctx, "")
+	if err != nil {
+		t.Fatalf("unexpected error while offline: %v", err)
+	}
+	if len(areas.Results) != 1 || areas.Results[0].Name != "canalave-city-area" {
+		t.Errorf("expected recorded page, got %v", areas.Results)
+	}
+	if _, err := offline.Pokemon(ctx, "pikachu"); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("expected not found for missing fixture, got %v", err)
+	}
+}
+
+func TestClientTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := api.NewClient(nil)
+	client.BaseURL = server.URL
+	client.Timeout = 10 * time.Millisecond
+	client.Retry = api.RetryPolicy{Attempts: 1}
+	if _, err := client.Pokemon(context.Background(), "pikachu"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+
+	client.Timeout = 0
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.Pokemon(ctx, "pikachu"); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected canceled, got %v", err)
+	}
+}
+
+func TestFetchServesStale(t *testing.T) {
+	var version atomic.Int32
+	client := apitest.NewClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte{byte('0' + version.Add(1))})
+	})
+	clock := apitest.NewClock(time.Now())
+	client.Cache.Close()
+	client.Cache = pokecache.NewCacheWithClock(time.Minute, clock.Now)
+	client.SetCachePolicies([]api.CachePolicy{{Pattern: "pokemon/*", TTL: time.Hour, Stale: time.Hour}})
+
+	ctx := context.Background()
+	url := client.URL("pokemon/pikachu")
+	if body, _ := client.Fetch(ctx, url); string(body) != "1" {
+		t.Fatalf("expected first response, got %q", body)
+	}
+	clock.Advance(90 * time.Minute)
+	if body, _ := client.Fetch(ctx, url); string(body) != "1" {
+		t.Errorf("expected stale response to be served at once, got %q", body)
+	}
+	client.Wait()
+	if body, _ := client.Fetch(ctx, url); string(body) != "2" {
+		t.Errorf("expected background refresh to replace the stale entry, got %q", body)
+	}
+	if n := version.Load(); n != 2 {
+		t.Errorf("expected 2 requests, got %v", n)
+	}
+}
+
+func TestFetchRevalidates(t *testing.T) {
+	var requests, notModified atomic.Int32
+	client := apitest.NewClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte(`{"id": 25, "name": "pikachu"}`))
+	})
+	clock := apitest.NewClock(time.Now())
+	client.Cache.Close()
+	client.Cache = pokecache.NewCacheWithClock(time.Minute, clock.Now)
+	client.SetCachePolicies([]api.CachePolicy{{Pattern: "pokemon/*", TTL: time.Hour}})
+
+	ctx := context.Background()
+	client.Pokemon(ctx, "pikachu")
+	clock.Advance(2 * time.Hour)
+	pokemon, err := client.Pokemon(ctx, "pikachu")
+	if err != nil || pokemon.ID != 25 {
+		t.Fatalf("expected revalidated pikachu, got #%v, %v", pokemon.ID, err)
+	}
+	if requests.Load() != 2 || notModified.Load() != 1 {
+		t.Errorf("expected a full and a conditional request, got %v requests and %v not modified", requests.Load(), notModified.Load())
+	}
+	if stats := client.Cache.Stats(); stats.Revalidations != 1 {
+		t.Errorf("expected 1 revalidation, got %+v", stats)
+	}
+	if _, freshness := client.Cache.Lookup(client.URL("pokemon/pikachu")); freshness != pokecache.Fresh {
+		t.Errorf("expected revalidated entry to be fresh again, got %v", freshness)
+	}
+}
+
+func TestFetchServesExpiredWhenUnreachable(t *testing.T) {
+	var down atomic.Bool
+	client := apitest.NewClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			http.Error(w, "maintenance", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"id": 25, "name": "pikachu"}`))
+	})
+	clock := apitest.NewClock(time.Now())
+	client.Cache.Close()
+	client.Cache = pokecache.NewCacheWithClock(time.Minute, clock.Now)
+	client.SetCachePolicies([]api.CachePolicy{{Pattern: "pokemon/*", TTL: time.Hour}})
+
+	ctx := context.Background()
+	client.Pokemon(ctx, "pikachu")
+	clock.Advance(2 * time.Hour)
+	down.Store(true)
+	pokemon, err := client.Pokemon(ctx, "pikachu")
+	if err != nil || pokemon.ID != 25 {
+		t.Fatalf("expected the expired pikachu while the API is down, got #%v, %v", pokemon.ID, err)
+	}
+	if _, freshness := client.Cache.Lookup(client.URL("pokemon/pikachu")); freshness != pokecache.Expired {
+		t.Errorf("expected the entry to stay expired, got %v", freshness)
+	}
+	if _, err := client.Pokemon(ctx, "bulbasaur"); err == nil {
+		t.Errorf("expected an uncached pokemon to fail while the API is down")
+	}
+}
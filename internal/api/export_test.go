@@ -0,0 +1,13 @@
+package api
+
+import "context"
+
+// Internals exposed to the external api_test package.
+
+var ParseRetryAfter = parseRetryAfter
+
+type Flight = flight
+
+func (f *Flight) Do(ctx context.Context, key string, fn func() ([]byte, error)) ([]byte, error) {
+	return f.do(ctx, key, fn)
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type Mode int
+
+const (
+	// ModeOnline fetches everything from the network.
+	ModeOnline Mode = iota
+	// ModeOffline serves every lookup from fixtures in DataDir.
+	ModeOffline
+	// ModeRecord fetches from the network and writes each response to DataDir.
+	ModeRecord
+)
+
+// fixturePath maps an API URL onto DataDir using the API's own layout, e.g.
+// ".../api/v2/pokemon/pikachu" -> "<DataDir>/pokemon/pikachu/index.json".
+// Query strings become an extra path segment so each page gets its own file.
+func (c *Client) fixturePath(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	base, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return "", err
+	}
+
+	rel := strings.TrimPrefix(u.Path, strings.TrimRight(base.Path, "/"))
+	rel = strings.Trim(rel, "/")
+	if rel == "" {
+		return "", fmt.Errorf("no resource path in %s", rawURL)
+	}
+	parts := strings.Split(rel, "/")
+	if query := u.Query(); len(query) > 0 {
+		parts = append(parts, query.Encode())
+	}
+	for _, part := range parts {
+		if part == ".." || part == "." {
+			return "", fmt.Errorf("invalid resource path %s", rawURL)
+		}
+	}
+	parts = append([]string{c.DataDir}, parts...)
+	return filepath.Join(append(parts, "index.json")...), nil
+}
+
+func (c *Client) readFixture(rawURL string) ([]byte, error) {
+	path, err := c.fixturePath(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("offline: no fixture for %s: %w", rawURL, ErrNotFound)
+	}
+	return data, err
+}
+
+func (c *Client) writeFixture(rawURL string, data []byte) error {
+	path, err := c.fixturePath(rawURL)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+	"sync"
+)
+
+// call is one fetch in progress that later lookups of the same URL wait on.
+type call struct {
+	done chan struct{}
+	body []byte
+	err  error
+	// panicked holds the value fn panicked with, re-raised in every caller.
+	panicked any
+}
+
+// flight coalesces concurrent fetches of the same key so only one of them
+// does the work. The zero value is ready to use.
+type flight struct {
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+// do runs fn for key unless a run for key is already in progress, in which
+// case it waits for that run and returns its result. fn runs on its own
+// goroutine and is not tied to any caller, so a caller whose ctx ends first,
+// including the one that started the run, returns early without cancelling
+// it for the others.
+func (f *flight) do(ctx context.Context, key string, fn func() ([]byte, error)) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	if f.calls == nil {
+		f.calls = make(map[string]*call)
+	}
+	c, ok := f.calls[key]
+	if !ok {
+		c = &call{done: make(chan struct{})}
+		f.calls[key] = c
+		go f.run(key, c, fn)
+	}
+	f.mu.Unlock()
+
+	select {
+	case <-c.done:
+		if c.panicked != nil {
+			panic(c.panicked)
+		}
+		return c.body, c.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// run calls fn and publishes its result, even if fn panics.
+func (f *flight) run(key string, c *call, fn func() ([]byte, error)) {
+	defer func() {
+		c.panicked = recover()
+		f.mu.Lock()
+		delete(f.calls, key)
+		f.mu.Unlock()
+		close(c.done)
+	}()
+	c.body, c.err = fn()
+}
@@ -0,0 +1,159 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/almasx/pokedexcli/internal/api"
+	"github.com/almasx/pokedexcli/internal/apitest"
+)
+
+// fetchAll fetches path from n goroutines at once and returns each result.
+func fetchAll(client *api.Client, path string, n int) ([][]byte, []error) {
+	bodies := make([][]byte, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			bodies[i], errs[i] = client.Fetch(context.Background(), client.URL(path))
+		}(i)
+	}
+	wg.Wait()
+	return bodies, errs
+}
+
+func TestFetchCoalesces(t *testing.T) {
+	var requests atomic.Int32
+	started, release := make(chan struct{}), make(chan struct{})
+	client := apitest.NewClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		w.Write([]byte(`{"id": 25, "name": "pikachu"}`))
+	})
+
+	go func() {
+		// Let the callers pile up behind the first request before answering.
+		<-started
+		close(release)
+	}()
+	bodies, errs := fetchAll(client, "pokemon/pikachu", 20)
+	for i := range bodies {
+		if errs[i] != nil || string(bodies[i]) != `{"id": 25, "name": "pikachu"}` {
+			t.Errorf("caller %v got %q, %v", i, bodies[i], errs[i])
+		}
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("expected 1 request, got %v", n)
+	}
+	if stats := client.Cache.Stats(); stats.Entries != 1 {
+		t.Errorf("expected 1 cache entry, got %v", stats.Entries)
+	}
+}
+
+func TestFetchCoalescesErrors(t *testing.T) {
+	var requests atomic.Int32
+	started, release := make(chan struct{}), make(chan struct{})
+	client := apitest.NewClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		http.NotFound(w, r)
+	})
+
+	go func() {
+		<-started
+		close(release)
+	}()
+	_, errs := fetchAll(client, "pokemon/pikachuu", 10)
+	for i, err := range errs {
+		if !errors.Is(err, api.ErrNotFound) {
+			t.Errorf("caller %v: expected not found, got %v", i, err)
+		}
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("expected 1 request, got %v", n)
+	}
+	if client.Cache.Len() != 0 {
+		t.Errorf("expected errors not to be cached")
+	}
+}
+
+func TestFetchWaiterCancelled(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	client := apitest.NewClient(t, func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.Write([]byte(`{}`))
+	})
+	defer close(release)
+
+	go client.Fetch(context.Background(), client.URL("pokemon/pikachu"))
+	<-started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.Fetch(ctx, client.URL("pokemon/pikachu")); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected cancelled waiter to return early, got %v", err)
+	}
+}
+
+func TestFetchLeaderCancelled(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	client := apitest.NewClient(t, func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.Write([]byte(`{}`))
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	leader := make(chan error)
+	go func() {
+		_, err := client.Fetch(ctx, client.URL("pokemon/pikachu"))
+		leader <- err
+	}()
+	<-started
+
+	waiter := make(chan []byte)
+	go func() {
+		body, err := client.Fetch(context.Background(), client.URL("pokemon/pikachu"))
+		if err != nil {
+			t.Errorf("expected the waiter to get the body, got %v", err)
+		}
+		waiter <- body
+	}()
+
+	cancel()
+	if err := <-leader; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected cancelled leader to return early, got %v", err)
+	}
+	close(release)
+	if body := <-waiter; string(body) != `{}` {
+		t.Errorf("expected the waiter to get the body, got %q", body)
+	}
+}
+
+func TestFlightPanic(t *testing.T) {
+	var f api.Flight
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("expected the panic to reach the caller, got %v", r)
+			}
+		}()
+		f.Do(context.Background(), "key", func() ([]byte, error) { panic("boom") })
+	}()
+
+	body, err := f.Do(context.Background(), "key", func() ([]byte, error) { return []byte("ok"), nil })
+	if err != nil || string(body) != "ok" {
+		t.Errorf("expected a fresh run after the panic, got %q, %v", body, err)
+	}
+}
@@ -0,0 +1,178 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/almasx/pokedexcli/internal/pokecache"
+)
+
+const day = 24 * time.Hour
+
+// CachePolicy says how long responses whose resource matches Pattern stay
+// fresh, and for how long after that they are still served while a newer
+// copy is fetched in the background.
+//
+// Patterns are matched with path.Match against the URL path below BaseURL,
+// e.g. "pokemon/*". A "?" starts a pattern for the query string, so
+// "location-area?offset=*" matches pages of the area list; without one, the
+// URL must have no query.
+type CachePolicy struct {
+	Pattern string
+	TTL     time.Duration
+	Stale   time.Duration
+}
+
+// DefaultCachePolicies treat game data as static and lists, which grow as
+// the API does, as good for a day.
+var DefaultCachePolicies = []CachePolicy{
+	{Pattern: "*?*", TTL: day, Stale: day},
+	{Pattern: "*/*", TTL: 30 * day, Stale: 7 * day},
+}
+
+func (p CachePolicy) matches(resource string) bool {
+	pathPattern, queryPattern, hasQuery := strings.Cut(p.Pattern, "?")
+	resourcePath, query, _ := strings.Cut(resource, "?")
+	if hasQuery != (query != "") {
+		return false
+	}
+	if ok, _ := path.Match(pathPattern, resourcePath); !ok {
+		return false
+	}
+	ok, _ := path.Match(queryPattern, query)
+	return ok
+}
+
+func (p CachePolicy) validate() error {
+	if p.Pattern == "" {
+		return fmt.Errorf("cache policy needs a pattern")
+	}
+	pathPattern, queryPattern, _ := strings.Cut(p.Pattern, "?")
+	for _, pattern := range []string{pathPattern, queryPattern} {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("cache policy %q: %w", p.Pattern, err)
+		}
+	}
+	if p.TTL < 0 || p.Stale < 0 {
+		return fmt.Errorf("cache policy %q: durations cannot be negative", p.Pattern)
+	}
+	return nil
+}
+
+// UnmarshalJSON reads a policy such as
+// {"pattern": "pokemon/*", "ttl": "30d", "stale": "12h"}.
+func (p *CachePolicy) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Pattern string `json:"pattern"`
+		TTL     string `json:"ttl"`
+		Stale   string `json:"stale"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	policy := CachePolicy{Pattern: raw.Pattern}
+	var err error
+	if policy.TTL, err = ParseTTL(raw.TTL); err != nil {
+		return fmt.Errorf("cache policy %q: ttl: %w", raw.Pattern, err)
+	}
+	if policy.Stale, err = ParseTTL(raw.Stale); err != nil {
+		return fmt.Errorf("cache policy %q: stale: %w", raw.Pattern, err)
+	}
+	if err := policy.validate(); err != nil {
+		return err
+	}
+	*p = policy
+	return nil
+}
+
+func (p CachePolicy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Pattern string `json:"pattern"`
+		TTL     string `json:"ttl"`
+		Stale   string `json:"stale"`
+	}{p.Pattern, FormatTTL(p.TTL), FormatTTL(p.Stale)})
+}
+
+// ParseTTL parses a duration as time.ParseDuration does, but also accepts
+// whole days such as "30d". An empty string is zero.
+func ParseTTL(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(n) * day, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// FormatTTL is the inverse of ParseTTL, preferring days where they fit.
+func FormatTTL(d time.Duration) string {
+	if d != 0 && d%day == 0 {
+		return strconv.Itoa(int(d/day)) + "d"
+	}
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// resource is the part of rawURL that cache policies match against: the
+// path below BaseURL without surrounding slashes, plus "?query" if any.
+func (c *Client) resource(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	rel := u.Path
+	if base, err := url.Parse(c.BaseURL); err == nil {
+		rel = strings.TrimPrefix(rel, strings.TrimRight(base.Path, "/"))
+	}
+	rel = strings.Trim(rel, "/")
+	if u.RawQuery != "" {
+		rel += "?" + u.RawQuery
+	}
+	return rel
+}
+
+// SetCachePolicies installs policies, checked in order, for responses the
+// client caches from now on. Responses matching none keep the cache's own TTL.
+func (c *Client) SetCachePolicies(policies []CachePolicy) error {
+	for _, policy := range policies {
+		if err := policy.validate(); err != nil {
+			return err
+		}
+	}
+	c.policies = policies
+	if c.Cache != nil {
+		c.Cache.SetPolicy(c.cacheTTL)
+	}
+	return nil
+}
+
+// CachePolicies returns the policies in effect, in the order they are checked.
+func (c *Client) CachePolicies() []CachePolicy {
+	return c.policies
+}
+
+func (c *Client) cacheTTL(key string) (pokecache.TTL, bool) {
+	resource := c.resource(key)
+	for _, policy := range c.policies {
+		if policy.matches(resource) {
+			return pokecache.TTL{Fresh: policy.TTL, Stale: policy.Stale}, true
+		}
+	}
+	return pokecache.TTL{}, false
+}
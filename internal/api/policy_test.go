@@ -0,0 +1,89 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestCachePolicyMatches(t *testing.T) {
+	cases := []struct {
+		pattern  string
+		resource string
+		expected bool
+	}{
+		{"pokemon/*", "pokemon/pikachu", true},
+		{"pokemon/*", "pokemon-species/25", false},
+		{"pokemon/*", "pokemon?limit=20", false},
+		{"location-area?offset=*", "location-area?offset=20&limit=20", true},
+		{"location-area?offset=*", "location-area/canalave-city-area", false},
+		{"*?*", "generation?limit=100000&offset=0", true},
+		{"*/*", "evolution-chain/10", true},
+	}
+	for _, c := range cases {
+		policy := CachePolicy{Pattern: c.pattern}
+		if actual := policy.matches(c.resource); actual != c.expected {
+			t.Errorf("%q matches %q: expected %v, got %v", c.pattern, c.resource, c.expected, actual)
+		}
+	}
+}
+
+func TestClientResource(t *testing.T) {
+	client := NewClient(nil)
+	cases := map[string]string{
+		client.URL("pokemon/pikachu"):                        "pokemon/pikachu",
+		"https://pokeapi.co/api/v2/pokemon-species/25/":      "pokemon-species/25",
+		"https://pokeapi.co/api/v2/location-area/?offset=20": "location-area?offset=20",
+	}
+	for rawURL, expected := range cases {
+		if actual := client.resource(rawURL); actual != expected {
+			t.Errorf("resource(%q) == %q, expected %q", rawURL, actual, expected)
+		}
+	}
+}
+
+func TestCachePolicyJSON(t *testing.T) {
+	var policies []CachePolicy
+	data := `[{"pattern": "pokemon/*", "ttl": "30d", "stale": "12h"}, {"pattern": "type/*", "ttl": "1h"}]`
+	if err := json.Unmarshal([]byte(data), &policies); err != nil {
+		t.Fatal(err)
+	}
+	expected := []CachePolicy{
+		{Pattern: "pokemon/*", TTL: 30 * day, Stale: 12 * time.Hour},
+		{Pattern: "type/*", TTL: time.Hour},
+	}
+	if len(policies) != len(expected) || policies[0] != expected[0] || policies[1] != expected[1] {
+		t.Errorf("expected %+v, got %+v", expected, policies)
+	}
+
+	for _, bad := range []string{
+		`{"ttl": "1d"}`,
+		`{"pattern": "pokemon/[", "ttl": "1d"}`,
+		`{"pattern": "pokemon/*", "ttl": "soon"}`,
+		`{"pattern": "pokemon/*", "ttl": "-1h"}`,
+	} {
+		var policy CachePolicy
+		if err := json.Unmarshal([]byte(bad), &policy); err == nil {
+			t.Errorf("expected %v to be rejected", bad)
+		}
+	}
+}
+
+func TestFormatTTL(t *testing.T) {
+	cases := map[time.Duration]string{
+		0:                         "0s",
+		30 * day:                  "30d",
+		6 * time.Hour:             "6h",
+		90 * time.Minute:          "1h30m",
+		time.Hour + 5*time.Second: "1h0m5s",
+		36 * time.Hour:            "36h",
+	}
+	for d, expected := range cases {
+		if actual := FormatTTL(d); actual != expected {
+			t.Errorf("FormatTTL(%v) == %q, expected %q", d, actual, expected)
+		}
+		if parsed, err := ParseTTL(expected); err != nil || parsed != d {
+			t.Errorf("ParseTTL(%q) == %v, %v, expected %v", expected, parsed, err, d)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/almasx/pokedexcli/internal/api"
+	"github.com/almasx/pokedexcli/internal/apitest"
+)
+
+// retryClient is a test client that retries quickly.
+func retryClient(t *testing.T, handler http.HandlerFunc) *api.Client {
+	client := apitest.NewClient(t, handler)
+	client.Retry = api.RetryPolicy{Attempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+	return client
+}
+
+func TestRetryTransient(t *testing.T) {
+	requests := 0
+	client := retryClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			http.Error(w, `{"oops": true}`, http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"id": 25, "name": "pikachu"}`))
+	})
+
+	pokemon, err := client.Pokemon(context.Background(), "pikachu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pokemon.ID != 25 || requests != 3 {
+		t.Errorf("expected pikachu after 3 requests, got #%v after %v", pokemon.ID, requests)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	requests := 0
+	client := retryClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.Error(w, "slow down", http.StatusTooManyRequests)
+	})
+
+	_, err := client.Pokemon(context.Background(), "pikachu")
+	if !errors.Is(err, api.ErrRateLimited) {
+		t.Errorf("expected rate limited, got %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("expected 3 attempts, got %v", requests)
+	}
+	if _, ok := client.Cache.Get(client.URL("pokemon/pikachu")); ok {
+		t.Errorf("expected 429 response not to be cached")
+	}
+}
+
+func TestNotFoundIsNotRetried(t *testing.T) {
+	requests := 0
+	client := retryClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.NotFound(w, r)
+	})
+
+	if _, err := client.Pokemon(context.Background(), "pikachuu"); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("expected not found, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 attempt, got %v", requests)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{"", 0},
+		{"3", 3 * time.Second},
+		{"-1", 0},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"soon", 0},
+	}
+	for _, c := range cases {
+		if actual := api.ParseRetryAfter(c.value, now); actual != c.expected {
+			t.Errorf("parseRetryAfter(%q) = %v, expected %v", c.value, actual, c.expected)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	policy := api.RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	cases := []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{1, 50 * time.Millisecond, 100 * time.Millisecond},
+		{2, 100 * time.Millisecond, 200 * time.Millisecond},
+		{3, 200 * time.Millisecond, 400 * time.Millisecond},
+		{10, 500 * time.Millisecond, time.Second},
+	}
+	for _, c := range cases {
+		for i := 0; i < 20; i++ {
+			if d := policy.Backoff(c.attempt); d < c.min || d > c.max {
+				t.Errorf("Backoff(%v) = %v, expected between %v and %v", c.attempt, d, c.min, c.max)
+			}
+		}
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	limiter := api.NewLimiter(100)
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("expected 5 requests at 100/s to take at least 40ms, took %v", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	limiter = api.NewLimiter(0.001)
+	limiter.Wait(context.Background())
+	if err := limiter.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected canceled, got %v", err)
+	}
+}
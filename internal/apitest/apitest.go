@@ -0,0 +1,65 @@
+// Package apitest runs fake PokeAPI servers for tests and points api.Clients
+// at them.
+package apitest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/almasx/pokedexcli/internal/api"
+	"github.com/almasx/pokedexcli/internal/pokecache"
+)
+
+// Serve answers each path in fixtures with its body, and any other path
+// with 404 Not Found.
+func Serve(fixtures map[string]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, ok := fixtures[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}
+}
+
+// NewClient returns a client for a test server running handler. It has its
+// own memory cache, no rate limit and a single attempt per request. The
+// server and cache are shut down when the test ends.
+func NewClient(t testing.TB, handler http.HandlerFunc) *api.Client {
+	server := httptest.NewServer(handler)
+	client := api.NewClient(pokecache.NewCache(time.Minute))
+	client.BaseURL = server.URL
+	client.Limiter = nil
+	client.Retry = api.RetryPolicy{Attempts: 1}
+	t.Cleanup(func() {
+		server.Close()
+		client.Cache.Close()
+	})
+	return client
+}
+
+// Clock is a pokecache.Clock that only moves when advanced.
+type Clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now}
+}
+
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
@@ -0,0 +1,319 @@
+package battle
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/almasx/pokedexcli/internal/api"
+	"github.com/almasx/pokedexcli/internal/cli"
+	"github.com/almasx/pokedexcli/internal/pokemon"
+	"github.com/almasx/pokedexcli/internal/trainer"
+	"github.com/almasx/pokedexcli/internal/types"
+)
+
+type Battle struct {
+	config      *cli.Config
+	out         io.Writer
+	chart       *types.Chart
+	rand        *rand.Rand
+	player      *Battler
+	wild        *Battler
+	captureRate int
+	// team holds every party pokemon that has been sent out, by ID.
+	team        map[int]*Battler
+	runAttempts int
+	outcome     string
+}
+
+func (b *Battle) sendOut(ctx context.Context, owned *trainer.Owned) error {
+	if battler, ok := b.team[owned.ID]; ok {
+		b.player = battler
+		return nil
+	}
+	moves, err := LoadMoves(ctx, b.config.Client, owned.Pokemon, owned.Level)
+	if err != nil {
+		return err
+	}
+	b.player = NewBattler(owned.Pokemon, owned.Level, moves)
+	b.team[owned.ID] = b.player
+	return nil
+}
+
+func (b *Battle) attack(attacker, defender *Battler, move Move) {
+	fmt.Fprintf(b.out, "%v used %v!\n", attacker.Name(), move.Name)
+	hit := Damage(attacker, defender, move, b.chart, b.rand)
+	switch {
+	case hit.Missed:
+		fmt.Fprintln(b.out, "It missed!")
+		return
+	case hit.Effectiveness == 0:
+		fmt.Fprintf(b.out, "It doesn't affect %v...\n", defender.Name())
+		return
+	}
+	if hit.Critical {
+		fmt.Fprintln(b.out, "A critical hit!")
+	}
+	if hit.Effectiveness > 1 {
+		fmt.Fprintln(b.out, "It's super effective!")
+	} else if hit.Effectiveness < 1 {
+		fmt.Fprintln(b.out, "It's not very effective...")
+	}
+	defender.HP = max(defender.HP-hit.Damage, 0)
+	if defender.Fainted() {
+		fmt.Fprintf(b.out, "%v fainted!\n", defender.Name())
+	}
+}
+
+func (b *Battle) wildMove() Move {
+	return b.wild.Moves[b.rand.Intn(len(b.wild.Moves))]
+}
+
+// turn resolves one round. A nil move means the player spent the turn on
+// something else, so only the wild pokemon acts.
+func (b *Battle) turn(move *Move) {
+	if move == nil {
+		b.attack(b.wild, b.player, b.wildMove())
+		return
+	}
+
+	playerFirst := b.player.Stats["speed"] > b.wild.Stats["speed"] ||
+		(b.player.Stats["speed"] == b.wild.Stats["speed"] && b.rand.Intn(2) == 0)
+	first, second := b.player, b.wild
+	firstMove, secondMove := *move, b.wildMove()
+	if !playerFirst {
+		first, second = second, first
+		firstMove, secondMove = secondMove, firstMove
+	}
+	b.attack(first, second, firstMove)
+	if !second.Fainted() {
+		b.attack(second, first, secondMove)
+	}
+}
+
+func (b *Battle) status() {
+	fmt.Fprintf(b.out, "Wild %v Lv%v  HP %v/%v\n", b.wild.Name(), b.wild.Level, b.wild.HP, b.wild.MaxHP)
+	fmt.Fprintf(b.out, "Your %v Lv%v  HP %v/%v\n", b.player.Name(), b.player.Level, b.player.HP, b.player.MaxHP)
+}
+
+func (b *Battle) printMoves() {
+	for i, move := range b.player.Moves {
+		fmt.Fprintf(b.out, "  %v. %v (%v, power %v)\n", i+1, move.Name, move.Type, move.Power)
+	}
+}
+
+func (b *Battle) chooseMove(arg string) (Move, bool) {
+	if i, err := strconv.Atoi(arg); err == nil && i >= 1 && i <= len(b.player.Moves) {
+		return b.player.Moves[i-1], true
+	}
+	for _, move := range b.player.Moves {
+		if move.Name == arg {
+			return move, true
+		}
+	}
+	return Move{}, false
+}
+
+func (b *Battle) canSwitch() bool {
+	for _, owned := range b.config.Collection.Party {
+		if battler, ok := b.team[owned.ID]; !ok || !battler.Fainted() {
+			return true
+		}
+	}
+	return false
+}
+
+func printBattleHelp(w io.Writer) {
+	fmt.Fprintln(w, "fight [move] - Attack with a move (lists moves without an argument)")
+	fmt.Fprintln(w, "ball [poke|great|ultra|master] - Throw a ball")
+	fmt.Fprintln(w, "switch <pokemon> - Send out another party pokemon")
+	fmt.Fprintln(w, "run - Try to get away")
+}
+
+// command handles one line of battle input and reports whether the battle is over.
+func (b *Battle) command(ctx context.Context, words []string) bool {
+	if b.player.Fainted() && words[0] != "switch" && words[0] != "run" && words[0] != "help" {
+		fmt.Fprintln(b.out, "your pokemon has fainted, switch to another one or run")
+		return false
+	}
+
+	switch words[0] {
+	case "fight":
+		if len(words) == 1 {
+			b.printMoves()
+			return false
+		}
+		move, ok := b.chooseMove(words[1])
+		if !ok {
+			fmt.Fprintln(b.out, "unknown move:", words[1])
+			return false
+		}
+		b.turn(&move)
+	case "ball":
+		name := ""
+		if len(words) > 1 {
+			name = words[1]
+		}
+		ball, err := pokemon.LookupBall(name)
+		if err != nil {
+			fmt.Fprintln(b.out, err)
+			return false
+		}
+		fmt.Fprintf(b.out, "You threw a %v!\n", ball.Name)
+		wild := pokemon.Wild{Level: b.wild.Level, MaxHP: b.wild.MaxHP, HP: b.wild.HP}
+		result := pokemon.Capture(b.captureRate, wild, ball, b.rand.Intn)
+		for i := 0; i < min(result.Shakes, 3); i++ {
+			fmt.Fprintln(b.out, "...the ball shook!")
+		}
+		if result.Caught {
+			fmt.Fprintf(b.out, "Gotcha! %v was caught!\n", b.wild.Name())
+			pokemon.AddCaught(b.config, b.wild.Pokemon, b.wild.Level)
+			b.outcome = "caught"
+			return true
+		}
+		fmt.Fprintf(b.out, "Oh no! %v broke free!\n", b.wild.Name())
+		b.turn(nil)
+	case "switch":
+		if len(words) != 2 {
+			fmt.Fprintln(b.out, "switch requires a pokemon")
+			return false
+		}
+		owned, err := b.config.Collection.Find(words[1])
+		if err != nil {
+			fmt.Fprintln(b.out, err)
+			return false
+		}
+		if !slices.Contains(b.config.Collection.Party, owned) {
+			fmt.Fprintln(b.out, owned.Name(), "is not in your party")
+			return false
+		}
+		if battler, ok := b.team[owned.ID]; ok && battler.Fainted() {
+			fmt.Fprintln(b.out, owned.Name(), "has fainted")
+			return false
+		}
+		wasFainted := b.player.Fainted()
+		if err := b.sendOut(ctx, owned); err != nil {
+			fmt.Fprintln(b.out, err)
+			return false
+		}
+		fmt.Fprintf(b.out, "Go, %v!\n", b.player.Name())
+		if !wasFainted {
+			b.turn(nil)
+		}
+	case "run":
+		b.runAttempts++
+		odds := b.player.Stats["speed"]*128/max(b.wild.Stats["speed"], 1) + 30*b.runAttempts
+		if b.player.Fainted() || odds > 255 || b.rand.Intn(256) < odds {
+			fmt.Fprintln(b.out, "Got away safely!")
+			b.outcome = "ran"
+			return true
+		}
+		fmt.Fprintln(b.out, "Can't escape!")
+		b.turn(nil)
+	case "help":
+		printBattleHelp(b.out)
+		return false
+	default:
+		fmt.Fprintln(b.out, "Unknown battle command, try help")
+		return false
+	}
+
+	if b.wild.Fainted() {
+		b.outcome = "won"
+		return true
+	}
+	if b.player.Fainted() && !b.canSwitch() {
+		fmt.Fprintln(b.out, "You have no pokemon left to fight! You ran back to safety.")
+		b.outcome = "lost"
+		return true
+	}
+	b.status()
+	return false
+}
+
+func CommandBattle(ctx context.Context, config *cli.Config, args []string, flags map[string]string) error {
+	wildData, err := config.Client.Pokemon(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	result, err := Start(ctx, config, wildData, pokemon.DefaultWildLevel)
+	if err != nil {
+		return err
+	}
+	return config.Out.Render(result)
+}
+
+// Result is how a battle ended: won, caught, ran, lost or abandoned.
+type Result struct {
+	Opponent string `json:"opponent"`
+	Level    int    `json:"level"`
+	Outcome  string `json:"outcome"`
+}
+
+// Text writes nothing, the battle narration has already told the story.
+func (r Result) Text(w io.Writer) {}
+
+// Start runs a battle against a wild pokemon at level until it ends.
+func Start(ctx context.Context, config *cli.Config, wildData api.GetPokemon, level int) (Result, error) {
+	result := Result{Opponent: wildData.Name, Level: level}
+	lead := config.Collection.Lead()
+	if lead == nil {
+		return result, fmt.Errorf("you need to catch a pokemon before you can battle")
+	}
+
+	chart, err := types.LoadChart(ctx, config.Client)
+	if err != nil {
+		return result, err
+	}
+	species, err := config.Client.PokemonSpecies(ctx, wildData.Species.Name)
+	if err != nil {
+		return result, err
+	}
+	wildMoves, err := LoadMoves(ctx, config.Client, wildData, level)
+	if err != nil {
+		return result, err
+	}
+
+	b := &Battle{
+		config:      config,
+		out:         config.Out.Log(),
+		chart:       chart,
+		rand:        config.Rand,
+		wild:        NewBattler(wildData, level, wildMoves),
+		captureRate: species.CaptureRate,
+		team:        make(map[int]*Battler),
+	}
+	if err := b.sendOut(ctx, lead); err != nil {
+		return result, err
+	}
+
+	config.MarkSeen(wildData.Name, wildData.SpeciesID())
+	fmt.Fprintf(b.out, "A wild %v appeared!\n", b.wild.Name())
+	fmt.Fprintf(b.out, "Go, %v!\n", b.player.Name())
+	b.status()
+	printBattleHelp(b.out)
+
+	for {
+		line, err := config.Input.ReadLine("Battle > ")
+		if err == io.EOF {
+			fmt.Fprintln(b.out)
+			result.Outcome = "abandoned"
+			return result, nil
+		}
+		if err != nil {
+			return result, err
+		}
+		words := strings.Fields(strings.ToLower(line))
+		if len(words) == 0 {
+			continue
+		}
+		if b.command(ctx, words) {
+			result.Outcome = b.outcome
+			return result, nil
+		}
+	}
+}
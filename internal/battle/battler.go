@@ -0,0 +1,116 @@
+package battle
+
+import (
+	"context"
+	"sort"
+
+	"github.com/almasx/pokedexcli/internal/api"
+	"github.com/almasx/pokedexcli/internal/pokemon"
+	"github.com/almasx/pokedexcli/internal/types"
+)
+
+type Move struct {
+	Name string
+	Type string
+	// Class is "physical", "special" or "status".
+	Class string
+	Power int
+	// Accuracy is a percentage; 0 means the move never misses.
+	Accuracy int
+}
+
+// struggle is used when a pokemon has no damaging moves at its level.
+var struggle = Move{Name: "struggle", Type: "normal", Class: "physical", Power: 50}
+
+func moveFromAPI(move api.GetMove) Move {
+	m := Move{
+		Name:  move.Name,
+		Type:  move.Type.Name,
+		Class: move.DamageClass.Name,
+	}
+	if move.Power != nil {
+		m.Power = *move.Power
+	}
+	if move.Accuracy != nil {
+		m.Accuracy = *move.Accuracy
+	}
+	return m
+}
+
+type Battler struct {
+	Pokemon api.GetPokemon
+	Level   int
+	Types   []string
+	Stats   map[string]int
+	MaxHP   int
+	HP      int
+	Moves   []Move
+}
+
+func NewBattler(pokemon_data api.GetPokemon, level int, moves []Move) *Battler {
+	b := &Battler{
+		Pokemon: pokemon_data,
+		Level:   level,
+		Types:   types.Of(pokemon_data),
+		Stats:   make(map[string]int),
+		MaxHP:   pokemon.HPStat(pokemon_data, level),
+		Moves:   moves,
+	}
+	b.HP = b.MaxHP
+	for _, stat := range pokemon_data.Stats {
+		b.Stats[stat.Stat.Name] = (2*stat.BaseStat+15)*level/100 + 5
+	}
+	if len(b.Moves) == 0 {
+		b.Moves = []Move{struggle}
+	}
+	return b
+}
+
+func (b *Battler) Name() string {
+	return b.Pokemon.Name
+}
+
+func (b *Battler) Fainted() bool {
+	return b.HP <= 0
+}
+
+const maxMoveLookups = 12
+
+// LoadMoves picks up to four damaging moves the pokemon learns by level-up at
+// or below level, preferring the most recently learned ones.
+func LoadMoves(ctx context.Context, client *api.Client, pokemon_data api.GetPokemon, level int) ([]Move, error) {
+	type candidate struct {
+		name  string
+		level int
+	}
+	var candidates []candidate
+	for _, move := range pokemon_data.Moves {
+		learnedAt := -1
+		for _, detail := range move.VersionGroupDetails {
+			if detail.MoveLearnMethod.Name == "level-up" && detail.LevelLearnedAt <= level {
+				learnedAt = max(learnedAt, detail.LevelLearnedAt)
+			}
+		}
+		if learnedAt >= 0 {
+			candidates = append(candidates, candidate{move.Move.Name, learnedAt})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].level > candidates[j].level
+	})
+
+	var moves []Move
+	for i, c := range candidates {
+		if len(moves) == 4 || i == maxMoveLookups {
+			break
+		}
+		move, err := client.Move(ctx, c.name)
+		if err != nil {
+			return nil, err
+		}
+		if move.Power != nil && *move.Power > 0 {
+			moves = append(moves, moveFromAPI(move))
+		}
+	}
+	return moves, nil
+}
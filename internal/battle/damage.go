@@ -0,0 +1,54 @@
+package battle
+
+import (
+	"math/rand"
+
+	"github.com/almasx/pokedexcli/internal/types"
+)
+
+type Hit struct {
+	Missed        bool
+	Damage        int
+	Effectiveness float64
+	Critical      bool
+}
+
+// Damage applies the standard damage formula with STAB, type effectiveness,
+// critical hits and the 85-100% random spread.
+func Damage(attacker, defender *Battler, move Move, chart *types.Chart, rng *rand.Rand) Hit {
+	if move.Accuracy > 0 && rng.Intn(100) >= move.Accuracy {
+		return Hit{Missed: true}
+	}
+
+	effectiveness, err := chart.Effectiveness(move.Type, defender.Types...)
+	if err != nil {
+		effectiveness = 1
+	}
+	hit := Hit{Effectiveness: effectiveness}
+	if effectiveness == 0 || move.Power == 0 {
+		return hit
+	}
+
+	attack, defense := attacker.Stats["attack"], defender.Stats["defense"]
+	if move.Class == "special" {
+		attack, defense = attacker.Stats["special-attack"], defender.Stats["special-defense"]
+	}
+	attack, defense = max(attack, 1), max(defense, 1)
+
+	base := float64((2*attacker.Level/5+2)*move.Power*attack/defense)/50 + 2
+
+	modifier := effectiveness * float64(85+rng.Intn(16)) / 100
+	for _, t := range attacker.Types {
+		if t == move.Type {
+			modifier *= 1.5
+			break
+		}
+	}
+	if rng.Intn(24) == 0 {
+		hit.Critical = true
+		modifier *= 1.5
+	}
+
+	hit.Damage = max(int(base*modifier), 1)
+	return hit
+}
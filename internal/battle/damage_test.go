@@ -0,0 +1,45 @@
+package battle
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/almasx/pokedexcli/internal/api"
+	"github.com/almasx/pokedexcli/internal/types"
+)
+
+func TestDamage(t *testing.T) {
+	electric := api.GetType{Name: "electric"}
+	electric.DamageRelations.DoubleDamageTo = []api.NamedResource{{Name: "water"}}
+	electric.DamageRelations.NoDamageTo = []api.NamedResource{{Name: "ground"}}
+	chart := types.NewChart([]api.GetType{electric})
+
+	stats := map[string]int{"attack": 100, "defense": 100, "special-attack": 100, "special-defense": 100}
+	attacker := &Battler{Level: 50, Types: []string{"electric"}, Stats: stats}
+	thunderbolt := Move{Name: "thunderbolt", Type: "electric", Class: "special", Power: 90, Accuracy: 100}
+	tackle := Move{Name: "tackle", Type: "normal", Class: "physical", Power: 40, Accuracy: 100}
+
+	cases := []struct {
+		name     string
+		defender []string
+		move     Move
+		min, max int
+	}{
+		// base damage is (22*90*100/100)/50+2 = 41.6
+		{"stab super effective", []string{"water"}, thunderbolt, 105, 188},
+		{"stab neutral", []string{"normal"}, thunderbolt, 53, 94},
+		{"immune", []string{"ground"}, thunderbolt, 0, 0},
+		{"no stab", []string{"normal"}, tackle, 16, 29},
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for _, c := range cases {
+		defender := &Battler{Level: 50, Types: c.defender, Stats: stats}
+		for i := 0; i < 50; i++ {
+			hit := Damage(attacker, defender, c.move, chart, rng)
+			if hit.Damage < c.min || hit.Damage > c.max {
+				t.Errorf("%s: damage %v outside [%v, %v]", c.name, hit.Damage, c.min, c.max)
+			}
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package cachepkg
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/almasx/pokedexcli/internal/api"
+	"github.com/almasx/pokedexcli/internal/cli"
+	"github.com/almasx/pokedexcli/internal/output"
+	"github.com/almasx/pokedexcli/internal/pokecache"
+)
+
+type cacheStats struct {
+	Memory      pokecache.Stats `json:"memory"`
+	DiskEnabled bool            `json:"disk_enabled"`
+	DiskDir     string          `json:"disk_dir,omitempty"`
+	DiskEntries int             `json:"disk_entries"`
+	DiskBytes   int64           `json:"disk_bytes"`
+}
+
+func kilobytes(n int64) string {
+	if n >= 1<<20 {
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	}
+	return fmt.Sprintf("%.1f KB", float64(n)/1024)
+}
+
+func (s cacheStats) Text(w io.Writer) {
+	m := s.Memory
+	fmt.Fprintf(w, "Memory entries: %v\n", m.Entries)
+	fmt.Fprintf(w, "Memory size: %v of %v\n", kilobytes(m.Bytes), kilobytes(m.MaxBytes))
+	lookups := m.Hits + m.Misses
+	if lookups > 0 {
+		fmt.Fprintf(w, "Hits: %v  Misses: %v (%.0f%% hit rate)\n", m.Hits, m.Misses, float64(m.Hits)*100/float64(lookups))
+	} else {
+		fmt.Fprintf(w, "Hits: 0  Misses: 0\n")
+	}
+	fmt.Fprintf(w, "Stale hits: %v  Revalidations: %v\n", m.StaleHits, m.Revalidations)
+	fmt.Fprintf(w, "Evictions: %v  Expirations: %v\n", m.Evictions, m.Expirations)
+	if !s.DiskEnabled {
+		fmt.Fprintln(w, "Disk cache: disabled")
+		return
+	}
+	fmt.Fprintf(w, "Disk cache: %v\n", s.DiskDir)
+	fmt.Fprintf(w, "Disk entries: %v\n", s.DiskEntries)
+	fmt.Fprintf(w, "Disk size: %v\n", kilobytes(s.DiskBytes))
+	fmt.Fprintf(w, "Disk hits: %v\n", m.DiskHits)
+	if m.DiskErrors > 0 {
+		fmt.Fprintf(w, "Disk write errors: %v\n", m.DiskErrors)
+	}
+}
+
+type policyList []api.CachePolicy
+
+func (l policyList) Text(w io.Writer) {
+	for _, p := range l {
+		fmt.Fprintf(w, "%-28v fresh %-6v stale %v\n", p.Pattern, api.FormatTTL(p.TTL), api.FormatTTL(p.Stale))
+	}
+}
+
+func (l policyList) Table() ([]string, [][]string) {
+	rows := make([][]string, len(l))
+	for i, p := range l {
+		rows[i] = []string{p.Pattern, api.FormatTTL(p.TTL), api.FormatTTL(p.Stale)}
+	}
+	return []string{"pattern", "ttl", "stale"}, rows
+}
+
+func CommandCache(ctx context.Context, config *cli.Config, args []string, flags map[string]string) error {
+
+	switch args[0] {
+	case "clear":
+		if err := config.Cache.Clear(); err != nil {
+			return err
+		}
+		return config.Out.Render(output.Message{Message: "Cache cleared"})
+	case "stats":
+		stats := cacheStats{Memory: config.Cache.Stats()}
+		if disk := config.Cache.Disk(); disk != nil {
+			ds, err := disk.Stats()
+			if err != nil {
+				return err
+			}
+			stats.DiskEnabled = true
+			stats.DiskDir = ds.Dir
+			stats.DiskEntries = ds.Entries
+			stats.DiskBytes = ds.Bytes
+		}
+		return config.Out.Render(stats)
+	case "policies":
+		return config.Out.Render(policyList(config.Client.CachePolicies()))
+	default:
+		return fmt.Errorf("unknown cache subcommand: %s", args[0])
+	}
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SplitFlags separates "--name value" and "--name=value" options from the
+// positional arguments. Names listed in valueFlags take a value; any other
+// flag is a boolean and is recorded as "true". Negative numbers such as "-5"
+// are positional, as is everything after a "--".
+func SplitFlags(args []string, valueFlags ...string) ([]string, map[string]string, error) {
+	var positional []string
+	flags := make(map[string]string)
+
+	takesValue := func(name string) bool {
+		for _, flag := range valueFlags {
+			if flag == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+		if _, err := strconv.ParseFloat(arg, 64); err == nil || !strings.HasPrefix(arg, "-") || arg == "-" {
+			positional = append(positional, arg)
+			continue
+		}
+		name := strings.TrimLeft(arg, "-")
+		if key, value, ok := strings.Cut(name, "="); ok {
+			flags[key] = value
+			continue
+		}
+		if !takesValue(name) {
+			flags[name] = "true"
+			continue
+		}
+		if i+1 >= len(args) {
+			return nil, nil, fmt.Errorf("flag --%s requires a value", name)
+		}
+		flags[name] = args[i+1]
+		i++
+	}
+	return positional, flags, nil
+}
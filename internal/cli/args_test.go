@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitFlags(t *testing.T) {
+	positional, flags, err := SplitFlags([]string{"pikachu", "--ball", "ultra", "--quiet", "--level=5"}, "ball", "level")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(positional, []string{"pikachu"}) {
+		t.Errorf("expected [pikachu], got %v", positional)
+	}
+	expected := map[string]string{"ball": "ultra", "quiet": "true", "level": "5"}
+	if !reflect.DeepEqual(flags, expected) {
+		t.Errorf("expected %v, got %v", expected, flags)
+	}
+
+	if _, _, err := SplitFlags([]string{"pikachu", "--ball"}, "ball"); err == nil {
+		t.Errorf("expected error for missing flag value")
+	}
+
+	positional, flags, err = SplitFlags([]string{"-5", "--ball", "-1", "--", "--not-a-flag"}, "ball")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(positional, []string{"-5", "--not-a-flag"}) || flags["ball"] != "-1" {
+		t.Errorf("expected negative numbers and everything after -- to be positional, got %v %v", positional, flags)
+	}
+}
@@ -1,13 +1,57 @@
 package cli
 
 import (
+	"math/rand"
+
 	"github.com/almasx/pokedexcli/internal/api"
+	"github.com/almasx/pokedexcli/internal/output"
 	"github.com/almasx/pokedexcli/internal/pokecache"
+	"github.com/almasx/pokedexcli/internal/trainer"
 )
 
 type Config struct {
-	Next string
-	Prev string
-	Cache *pokecache.Cache
+	Next   string
+	Prev   string
+	Cache  *pokecache.Cache
+	Client *api.Client
+	// Pokedex holds every species caught so far, by name.
 	Pokedex map[string]api.GetPokemon
+	// Seen maps every species encountered so far to its national dex number.
+	Seen       map[string]int
+	Collection *trainer.Collection
+	// Areas holds every location area listed by map so far, for completion.
+	Areas map[string]bool
+	// Location is the location area the player is currently in.
+	Location string
+	// Version is the game version whose encounter tables walk uses.
+	Version  string
+	SaveDir  string
+	SaveSlot string
+	Seed     int64
+	Rand     *rand.Rand
+	Input    LineReader
+	Out      *output.Renderer
+}
+
+// SetSeed installs a fresh RNG seeded with seed. Every random decision goes
+// through config.Rand so a session can be replayed from its seed.
+func (c *Config) SetSeed(seed int64) {
+	c.Seed = seed
+	c.Rand = rand.New(rand.NewSource(seed))
+}
+
+// MarkSeen records that a species has been encountered.
+func (c *Config) MarkSeen(name string, id int) {
+	if c.Seen == nil {
+		c.Seen = make(map[string]int)
+	}
+	c.Seen[name] = id
+}
+
+// MarkArea records a location area name the player has been shown.
+func (c *Config) MarkArea(name string) {
+	if c.Areas == nil {
+		c.Areas = make(map[string]bool)
+	}
+	c.Areas[name] = true
 }
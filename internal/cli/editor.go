@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/peterh/liner"
+)
+
+// Completer returns the candidates for the last word of line.
+type Completer func(line string) []string
+
+// Editor is the interactive LineReader: arrow-key history, cursor movement,
+// Ctrl-R reverse search and tab completion. History is loaded from and saved
+// to historyPath.
+type Editor struct {
+	state       *liner.State
+	historyPath string
+}
+
+func NewEditor(historyPath string, complete Completer) *Editor {
+	state := liner.NewLiner()
+	state.SetCtrlCAborts(true)
+	state.SetTabCompletionStyle(liner.TabPrints)
+	state.SetWordCompleter(func(line string, pos int) (string, []string, string) {
+		head := line[:pos]
+		start := strings.LastIndex(head, " ") + 1
+		completions := complete(head)
+		if len(completions) == 1 {
+			// A unique match is finished, so move on to the next word.
+			completions[0] += " "
+		}
+		return head[:start], completions, line[pos:]
+	})
+	if f, err := os.Open(historyPath); err == nil {
+		state.ReadHistory(f)
+		f.Close()
+	}
+	return &Editor{state: state, historyPath: historyPath}
+}
+
+// ReadLine returns the next line, or io.EOF on Ctrl-D. Ctrl-C discards the
+// line being typed and returns an empty one.
+func (e *Editor) ReadLine(prompt string) (string, error) {
+	line, err := e.state.Prompt(prompt)
+	if err == liner.ErrPromptAborted {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(line) != "" {
+		e.state.AppendHistory(line)
+	}
+	return line, nil
+}
+
+// Close saves the history and restores the terminal.
+func (e *Editor) Close() error {
+	defer e.state.Close()
+	if e.historyPath == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(e.historyPath), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(e.historyPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = e.state.WriteHistory(f)
+	return err
+}
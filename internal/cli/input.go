@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// LineReader is where the REPL, and sub-prompts such as battle, read input from.
+type LineReader interface {
+	ReadLine(prompt string) (string, error)
+}
+
+type ScannerInput struct {
+	scanner *bufio.Scanner
+	prompt  bool
+}
+
+// NewScannerInput reads lines from r. Prompts are only printed when prompt is
+// set, so scripts and piped input stay quiet.
+func NewScannerInput(r io.Reader, prompt bool) *ScannerInput {
+	return &ScannerInput{scanner: bufio.NewScanner(r), prompt: prompt}
+}
+
+// ReadLine prints prompt and returns the next line, or io.EOF when input ends.
+func (s *ScannerInput) ReadLine(prompt string) (string, error) {
+	if s.prompt {
+		fmt.Print(prompt)
+	}
+	if !s.scanner.Scan() {
+		if err := s.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	return s.scanner.Text(), nil
+}
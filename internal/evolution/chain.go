@@ -0,0 +1,185 @@
+package evolution
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/almasx/pokedexcli/internal/api"
+)
+
+// State is what we know about a caught pokemon when checking evolution conditions.
+type State struct {
+	Level int
+	// Item is the item being used on the pokemon, if any.
+	Item string
+	Hour int
+}
+
+func matchesTimeOfDay(want string, hour int) bool {
+	switch want {
+	case "":
+		return true
+	case "day":
+		return hour >= 6 && hour < 18
+	case "night":
+		return hour >= 18 || hour < 6
+	case "dusk":
+		return hour == 17
+	}
+	return false
+}
+
+// Check reports whether detail is satisfied by state, and if not, why.
+func Check(detail api.EvolutionDetail, state State) (bool, string) {
+	switch detail.Trigger.Name {
+	case "level-up":
+	case "use-item":
+		if detail.Item == nil || detail.Item.Name != state.Item {
+			return false, fmt.Sprintf("needs %v", Describe(detail))
+		}
+	default:
+		return false, fmt.Sprintf("%v evolutions are not supported", detail.Trigger.Name)
+	}
+
+	if detail.MinLevel != nil && state.Level < *detail.MinLevel {
+		return false, fmt.Sprintf("needs level %v (is %v)", *detail.MinLevel, state.Level)
+	}
+	if !matchesTimeOfDay(detail.TimeOfDay, state.Hour) {
+		return false, fmt.Sprintf("only evolves during the %v", detail.TimeOfDay)
+	}
+
+	untracked := []struct {
+		set  bool
+		name string
+	}{
+		{detail.Gender != nil, "gender"},
+		{detail.HeldItem != nil, "held item"},
+		{detail.KnownMove != nil, "known move"},
+		{detail.KnownMoveType != nil, "known move type"},
+		{detail.Location != nil, "location"},
+		{detail.MinHappiness != nil, "friendship"},
+		{detail.MinBeauty != nil, "beauty"},
+		{detail.MinAffection != nil, "affection"},
+		{detail.NeedsOverworldRain, "overworld rain"},
+		{detail.PartySpecies != nil, "party species"},
+		{detail.PartyType != nil, "party type"},
+		{detail.RelativePhysicalStats != nil, "relative physical stats"},
+		{detail.TradeSpecies != nil, "trade species"},
+		{detail.TurnUpsideDown, "turning the console upside down"},
+	}
+	for _, condition := range untracked {
+		if condition.set {
+			return false, fmt.Sprintf("depends on %v, which is not tracked", condition.name)
+		}
+	}
+	return true, ""
+}
+
+// Describe renders an evolution detail as a short phrase, e.g. "level 16" or
+// "use water-stone".
+func Describe(detail api.EvolutionDetail) string {
+	var parts []string
+	switch detail.Trigger.Name {
+	case "level-up":
+		if detail.MinLevel != nil {
+			parts = append(parts, fmt.Sprintf("level %v", *detail.MinLevel))
+		} else {
+			parts = append(parts, "level up")
+		}
+	case "use-item":
+		if detail.Item != nil {
+			parts = append(parts, "use "+detail.Item.Name)
+		}
+	case "trade":
+		parts = append(parts, "trade")
+	default:
+		parts = append(parts, detail.Trigger.Name)
+	}
+
+	if detail.Item != nil && detail.Trigger.Name != "use-item" {
+		parts = append(parts, "with "+detail.Item.Name)
+	}
+	if detail.HeldItem != nil {
+		parts = append(parts, "holding "+detail.HeldItem.Name)
+	}
+	if detail.MinHappiness != nil {
+		parts = append(parts, fmt.Sprintf("friendship %v", *detail.MinHappiness))
+	}
+	if detail.MinAffection != nil {
+		parts = append(parts, fmt.Sprintf("affection %v", *detail.MinAffection))
+	}
+	if detail.MinBeauty != nil {
+		parts = append(parts, fmt.Sprintf("beauty %v", *detail.MinBeauty))
+	}
+	if detail.KnownMove != nil {
+		parts = append(parts, "knowing "+detail.KnownMove.Name)
+	}
+	if detail.KnownMoveType != nil {
+		parts = append(parts, "knowing a "+detail.KnownMoveType.Name+" move")
+	}
+	if detail.Location != nil {
+		parts = append(parts, "at "+detail.Location.Name)
+	}
+	if detail.PartySpecies != nil {
+		parts = append(parts, "with "+detail.PartySpecies.Name+" in party")
+	}
+	if detail.PartyType != nil {
+		parts = append(parts, "with a "+detail.PartyType.Name+" type in party")
+	}
+	if detail.TradeSpecies != nil {
+		parts = append(parts, "for "+detail.TradeSpecies.Name)
+	}
+	if detail.TimeOfDay != "" {
+		parts = append(parts, "during the "+detail.TimeOfDay)
+	}
+	if detail.NeedsOverworldRain {
+		parts = append(parts, "while raining")
+	}
+	if detail.TurnUpsideDown {
+		parts = append(parts, "upside down")
+	}
+	return strings.Join(parts, ", ")
+}
+
+func describeAll(details []api.EvolutionDetail) string {
+	var descriptions []string
+	for _, detail := range details {
+		descriptions = append(descriptions, Describe(detail))
+	}
+	return strings.Join(descriptions, " or ")
+}
+
+// Find returns the link for species within the chain, or nil.
+func Find(link *api.ChainLink, species string) *api.ChainLink {
+	if link.Species.Name == species {
+		return link
+	}
+	for i := range link.EvolvesTo {
+		if found := Find(&link.EvolvesTo[i], species); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// PrintTree writes the chain rooted at link as an indented tree.
+func PrintTree(w io.Writer, link api.ChainLink) {
+	fmt.Fprintln(w, link.Species.Name)
+	printChildren(w, link, "")
+}
+
+func printChildren(w io.Writer, link api.ChainLink, indent string) {
+	for i, child := range link.EvolvesTo {
+		branch, next := "├── ", "│   "
+		if i == len(link.EvolvesTo)-1 {
+			branch, next = "└── ", "    "
+		}
+		fmt.Fprintf(w, "%v%v%v", indent, branch, child.Species.Name)
+		if len(child.EvolutionDetails) > 0 {
+			fmt.Fprintf(w, " (%v)", describeAll(child.EvolutionDetails))
+		}
+		fmt.Fprintln(w)
+		printChildren(w, child, indent+next)
+	}
+}
@@ -0,0 +1,57 @@
+package evolution
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/almasx/pokedexcli/internal/api"
+)
+
+const eeveeChain = `{
+	"species": {"name": "eevee"},
+	"evolves_to": [
+		{"species": {"name": "vaporeon"}, "evolution_details": [{"trigger": {"name": "use-item"}, "item": {"name": "water-stone"}}]},
+		{"species": {"name": "espeon"}, "evolution_details": [{"trigger": {"name": "level-up"}, "min_happiness": 160, "time_of_day": "day"}]}
+	]
+}`
+
+func TestChain(t *testing.T) {
+	chain := api.ChainLink{}
+	if err := json.Unmarshal([]byte(eeveeChain), &chain); err != nil {
+		t.Fatal(err)
+	}
+
+	out := &strings.Builder{}
+	PrintTree(out, chain)
+	expected := "eevee\n├── vaporeon (use water-stone)\n└── espeon (level up, friendship 160, during the day)\n"
+	if out.String() != expected {
+		t.Errorf("expected tree:\n%v\ngot:\n%v", expected, out.String())
+	}
+
+	if Find(&chain, "espeon") == nil || Find(&chain, "pikachu") != nil {
+		t.Errorf("Find returned the wrong link")
+	}
+
+	cases := []struct {
+		target   int
+		state    State
+		expected bool
+	}{
+		{0, State{Item: "water-stone"}, true},
+		{0, State{Item: "fire-stone"}, false},
+		{1, State{Hour: 12}, false},
+	}
+	for _, c := range cases {
+		detail := chain.EvolvesTo[c.target].EvolutionDetails[0]
+		if ok, reason := Check(detail, c.state); ok != c.expected {
+			t.Errorf("Check(%v, %+v) == %v (%v), expected %v", Describe(detail), c.state, ok, reason, c.expected)
+		}
+	}
+
+	// Friendship is not tracked, so it is reported rather than failed.
+	espeon := chain.EvolvesTo[1].EvolutionDetails[0]
+	if _, reason := Check(espeon, State{Hour: 12}); !strings.Contains(reason, "friendship, which is not tracked") {
+		t.Errorf("expected friendship to be reported as untracked, got %q", reason)
+	}
+}
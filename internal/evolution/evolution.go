@@ -0,0 +1,148 @@
+package evolution
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/almasx/pokedexcli/internal/api"
+	"github.com/almasx/pokedexcli/internal/cli"
+)
+
+func fetchChain(ctx context.Context, config *cli.Config, pokemon_data api.GetPokemon) (api.GetPokemonSpecies, api.GetEvolutionChain, error) {
+	species, err := config.Client.PokemonSpecies(ctx, pokemon_data.Species.Name)
+	if err != nil {
+		return api.GetPokemonSpecies{}, api.GetEvolutionChain{}, err
+	}
+	chain, err := config.Client.EvolutionChain(ctx, species.EvolutionChain.URL)
+	if err != nil {
+		return api.GetPokemonSpecies{}, api.GetEvolutionChain{}, err
+	}
+	return species, chain, nil
+}
+
+type treeNode struct {
+	Species   string     `json:"species"`
+	Condition string     `json:"condition,omitempty"`
+	EvolvesTo []treeNode `json:"evolves_to,omitempty"`
+
+	link api.ChainLink
+}
+
+func newTreeNode(link api.ChainLink) treeNode {
+	node := treeNode{
+		Species:   link.Species.Name,
+		Condition: describeAll(link.EvolutionDetails),
+		link:      link,
+	}
+	for _, child := range link.EvolvesTo {
+		node.EvolvesTo = append(node.EvolvesTo, newTreeNode(child))
+	}
+	return node
+}
+
+func (n treeNode) Text(w io.Writer) {
+	PrintTree(w, n.link)
+}
+
+func (n treeNode) Table() ([]string, [][]string) {
+	var rows [][]string
+	var walk func(parent string, node treeNode)
+	walk = func(parent string, node treeNode) {
+		rows = append(rows, []string{node.Species, parent, node.Condition})
+		for _, child := range node.EvolvesTo {
+			walk(node.Species, child)
+		}
+	}
+	walk("", n)
+	return []string{"species", "evolves_from", "condition"}, rows
+}
+
+type evolveResult struct {
+	ID   int    `json:"id"`
+	From string `json:"from"`
+	Into string `json:"into"`
+}
+
+func (r evolveResult) Text(w io.Writer) {
+	fmt.Fprintf(w, "What? %v is evolving!\n", r.From)
+	fmt.Fprintf(w, "Congratulations! Your %v evolved into %v!\n", r.From, r.Into)
+}
+
+func CommandEvolution(ctx context.Context, config *cli.Config, args []string, flags map[string]string) error {
+
+	pokemon_data, err := config.Client.Pokemon(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	_, chain, err := fetchChain(ctx, config, pokemon_data)
+	if err != nil {
+		return err
+	}
+	return config.Out.Render(newTreeNode(chain.Chain))
+}
+
+func CommandEvolve(ctx context.Context, config *cli.Config, args []string, flags map[string]string) error {
+	owned, err := config.Collection.Find(args[0])
+	if err != nil {
+		return err
+	}
+	name := owned.Name()
+
+	species, chain, err := fetchChain(ctx, config, owned.Pokemon)
+	if err != nil {
+		return err
+	}
+	link := Find(&chain.Chain, species.Name)
+	if link == nil || len(link.EvolvesTo) == 0 {
+		return fmt.Errorf("%s does not evolve", name)
+	}
+
+	state := State{
+		Level: owned.Level,
+		Item:  flags["item"],
+		Hour:  time.Now().Hour(),
+	}
+
+	var target *api.ChainLink
+	for i := range link.EvolvesTo {
+		child := &link.EvolvesTo[i]
+		if flags["into"] != "" && child.Species.Name != flags["into"] {
+			continue
+		}
+		for _, detail := range child.EvolutionDetails {
+			ok, reason := Check(detail, state)
+			if ok {
+				target = child
+				break
+			}
+			config.Out.Printf("%v cannot evolve into %v: %v\n", name, child.Species.Name, reason)
+		}
+		if target != nil {
+			break
+		}
+	}
+	if target == nil {
+		return fmt.Errorf("%s cannot evolve yet", name)
+	}
+
+	nextSpecies, err := config.Client.PokemonSpecies(ctx, target.Species.Name)
+	if err != nil {
+		return err
+	}
+	nextName := nextSpecies.Name
+	for _, variety := range nextSpecies.Varieties {
+		if variety.IsDefault {
+			nextName = variety.Pokemon.Name
+		}
+	}
+	next, err := config.Client.Pokemon(ctx, nextName)
+	if err != nil {
+		return err
+	}
+
+	owned.Pokemon = next
+	config.Pokedex[nextName] = next
+	return config.Out.Render(evolveResult{ID: owned.ID, From: name, Into: nextName})
+}
@@ -0,0 +1,125 @@
+package explorepkg
+
+import (
+	"sort"
+
+	"github.com/almasx/pokedexcli/internal/api"
+)
+
+// Encounter aggregates the encounter slots of one pokemon for a single game
+// version and encounter method.
+type Encounter struct {
+	Pokemon  string `json:"pokemon"`
+	URL      string `json:"-"`
+	Version  string `json:"version"`
+	Method   string `json:"method"`
+	MinLevel int    `json:"min_level"`
+	MaxLevel int    `json:"max_level"`
+	// Chance is the summed percentage of every slot for this method.
+	Chance int `json:"chance"`
+}
+
+// Encounters flattens an area's encounter table, keeping only rows that match
+// version and method when they are non-empty.
+func Encounters(area api.GetLocationAreaPokemons, version, method string) []Encounter {
+	type key struct{ pokemon, version, method string }
+	byKey := map[key]*Encounter{}
+	var order []key
+
+	for _, pokemon := range area.PokemonEncounters {
+		for _, versionDetail := range pokemon.VersionDetails {
+			if version != "" && versionDetail.Version.Name != version {
+				continue
+			}
+			for _, detail := range versionDetail.EncounterDetails {
+				if method != "" && detail.Method.Name != method {
+					continue
+				}
+				k := key{pokemon.Pokemon.Name, versionDetail.Version.Name, detail.Method.Name}
+				e, ok := byKey[k]
+				if !ok {
+					e = &Encounter{
+						Pokemon:  k.pokemon,
+						URL:      pokemon.Pokemon.URL,
+						Version:  k.version,
+						Method:   k.method,
+						MinLevel: detail.MinLevel,
+						MaxLevel: detail.MaxLevel,
+					}
+					byKey[k] = e
+					order = append(order, k)
+				}
+				e.MinLevel = min(e.MinLevel, detail.MinLevel)
+				e.MaxLevel = max(e.MaxLevel, detail.MaxLevel)
+				e.Chance += detail.Chance
+			}
+		}
+	}
+
+	encounters := make([]Encounter, 0, len(order))
+	for _, k := range order {
+		encounters = append(encounters, *byKey[k])
+	}
+	sort.SliceStable(encounters, func(i, j int) bool {
+		a, b := encounters[i], encounters[j]
+		if a.Version != b.Version {
+			return a.Version < b.Version
+		}
+		if a.Method != b.Method {
+			return a.Method < b.Method
+		}
+		return a.Chance > b.Chance
+	})
+	return encounters
+}
+
+// Summary aggregates one pokemon's encounters for a method across versions.
+type Summary struct {
+	Pokemon   string   `json:"pokemon"`
+	Method    string   `json:"method"`
+	MinLevel  int      `json:"min_level"`
+	MaxLevel  int      `json:"max_level"`
+	MinChance int      `json:"min_chance"`
+	MaxChance int      `json:"max_chance"`
+	Versions  []string `json:"versions"`
+}
+
+func Summarize(encounters []Encounter) []Summary {
+	type key struct{ pokemon, method string }
+	byKey := map[key]*Summary{}
+	var order []key
+
+	for _, e := range encounters {
+		k := key{e.Pokemon, e.Method}
+		s, ok := byKey[k]
+		if !ok {
+			s = &Summary{
+				Pokemon:   e.Pokemon,
+				Method:    e.Method,
+				MinLevel:  e.MinLevel,
+				MaxLevel:  e.MaxLevel,
+				MinChance: e.Chance,
+				MaxChance: e.Chance,
+			}
+			byKey[k] = s
+			order = append(order, k)
+		}
+		s.MinLevel = min(s.MinLevel, e.MinLevel)
+		s.MaxLevel = max(s.MaxLevel, e.MaxLevel)
+		s.MinChance = min(s.MinChance, e.Chance)
+		s.MaxChance = max(s.MaxChance, e.Chance)
+		s.Versions = append(s.Versions, e.Version)
+	}
+
+	summaries := make([]Summary, 0, len(order))
+	for _, k := range order {
+		summaries = append(summaries, *byKey[k])
+	}
+	sort.SliceStable(summaries, func(i, j int) bool {
+		if summaries[i].Method != summaries[j].Method {
+			return summaries[i].Method < summaries[j].Method
+		}
+		return summaries[i].MaxChance > summaries[j].MaxChance
+	})
+	return summaries
+}
@@ -0,0 +1,56 @@
+package explorepkg
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/almasx/pokedexcli/internal/api"
+)
+
+const routeOne = `{
+	"name": "kanto-route-1-area",
+	"pokemon_encounters": [
+		{"pokemon": {"name": "pidgey"}, "version_details": [
+			{"version": {"name": "red"}, "encounter_details": [
+				{"min_level": 2, "max_level": 2, "chance": 15, "method": {"name": "walk"}},
+				{"min_level": 3, "max_level": 5, "chance": 40, "method": {"name": "walk"}}
+			]},
+			{"version": {"name": "blue"}, "encounter_details": [
+				{"min_level": 3, "max_level": 7, "chance": 35, "method": {"name": "walk"}}
+			]}
+		]},
+		{"pokemon": {"name": "rattata"}, "version_details": [
+			{"version": {"name": "red"}, "encounter_details": [
+				{"min_level": 2, "max_level": 4, "chance": 45, "method": {"name": "walk"}}
+			]}
+		]}
+	]
+}`
+
+func TestEncounters(t *testing.T) {
+	area := api.GetLocationAreaPokemons{}
+	if err := json.Unmarshal([]byte(routeOne), &area); err != nil {
+		t.Fatal(err)
+	}
+
+	red := Encounters(area, "red", "walk")
+	expected := []Encounter{
+		{Pokemon: "pidgey", Version: "red", Method: "walk", MinLevel: 2, MaxLevel: 5, Chance: 55},
+		{Pokemon: "rattata", Version: "red", Method: "walk", MinLevel: 2, MaxLevel: 4, Chance: 45},
+	}
+	if len(red) != len(expected) {
+		t.Fatalf("expected %v encounters, got %+v", len(expected), red)
+	}
+	for i := range red {
+		if red[i] != expected[i] {
+			t.Errorf("encounter %v: expected %+v, got %+v", i, expected[i], red[i])
+		}
+	}
+
+	summaries := Summarize(Encounters(area, "", ""))
+	pidgey := summaries[0]
+	if pidgey.Pokemon != "pidgey" || pidgey.MinLevel != 2 || pidgey.MaxLevel != 7 ||
+		pidgey.MinChance != 35 || pidgey.MaxChance != 55 || len(pidgey.Versions) != 2 {
+		t.Errorf("unexpected pidgey summary: %+v", pidgey)
+	}
+}
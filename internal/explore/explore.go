@@ -1,68 +1,126 @@
 package explorepkg
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"io"
-	"net/http"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/almasx/pokedexcli/internal/api"
 	"github.com/almasx/pokedexcli/internal/cli"
 )
 
+func levels(lo, hi int) string {
+	if lo == hi {
+		return fmt.Sprint(lo)
+	}
+	return fmt.Sprintf("%v-%v", lo, hi)
+}
 
+type foundPokemon struct {
+	Area    string   `json:"area"`
+	Pokemon []string `json:"pokemon"`
+}
 
-func fetchLocationAreaPokemons(url string, config *cli.Config) (api.GetLocationAreaPokemons, error) {
-	res := api.GetLocationAreaPokemons{}
+func (f foundPokemon) Text(w io.Writer) {
+	fmt.Fprintln(w, "Found Pokemon:")
+	for _, pokemon := range f.Pokemon {
+		fmt.Fprintln(w, " - ", pokemon)
+	}
+}
 
-	if data, ok := config.Cache.Get(url); ok {
-		err := json.Unmarshal(data, &res)
-		if err != nil {
-			return res, err
-		}
-		return res, nil
+func (f foundPokemon) Table() ([]string, [][]string) {
+	rows := make([][]string, len(f.Pokemon))
+	for i, pokemon := range f.Pokemon {
+		rows[i] = []string{f.Area, pokemon}
 	}
-	
-	data, err := http.Get(url)
-	if err != nil {
-		return api.GetLocationAreaPokemons{}, err
+	return []string{"area", "pokemon"}, rows
+}
+
+type encounterTable []Encounter
+
+func (t encounterTable) Text(w io.Writer) {
+	if len(t) == 0 {
+		fmt.Fprintln(w, "No encounters match those filters")
+		return
 	}
-	defer data.Body.Close()
-	body, err := io.ReadAll(data.Body)
-	if err != nil {
-		return api.GetLocationAreaPokemons{}, err
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Pokemon\tVersion\tMethod\tLevels\tChance")
+	for _, e := range t {
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v%%\n", e.Pokemon, e.Version, e.Method, levels(e.MinLevel, e.MaxLevel), e.Chance)
 	}
-	config.Cache.Add(url, body)
-	err = json.Unmarshal(body, &res)
-	if err != nil {
-		return api.GetLocationAreaPokemons{}, err
+	tw.Flush()
+}
+
+type summaryTable []Summary
+
+func (t summaryTable) Text(w io.Writer) {
+	if len(t) == 0 {
+		fmt.Fprintln(w, "No encounters match those filters")
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Pokemon\tMethod\tLevels\tChance\tVersions")
+	for _, s := range t {
+		chance := fmt.Sprintf("%v%%", s.MinChance)
+		if s.MinChance != s.MaxChance {
+			chance = fmt.Sprintf("%v-%v%%", s.MinChance, s.MaxChance)
+		}
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\n", s.Pokemon, s.Method, levels(s.MinLevel, s.MaxLevel), chance, strings.Join(s.Versions, ", "))
 	}
-	return res, nil
+	tw.Flush()
 }
 
-func CommandExplore(config *cli.Config, args []string) error {
-	if len(args) != 1 {
-		fmt.Println("explore requires a location area")
-		return fmt.Errorf("explore requires a location area")
+func (t summaryTable) Table() ([]string, [][]string) {
+	rows := make([][]string, len(t))
+	for i, s := range t {
+		rows[i] = []string{s.Pokemon, s.Method, fmt.Sprint(s.MinLevel), fmt.Sprint(s.MaxLevel),
+			fmt.Sprint(s.MinChance), fmt.Sprint(s.MaxChance), strings.Join(s.Versions, " ")}
+	}
+	return []string{"pokemon", "method", "min_level", "max_level", "min_chance", "max_chance", "versions"}, rows
+}
+
+func CommandExplore(ctx context.Context, config *cli.Config, args []string, flags map[string]string) error {
+	location_area := config.Location
+	if len(args) == 1 {
+		location_area = args[0]
 	}
-	location_area := args[0]
 	if location_area == "" {
-		fmt.Println("location area is required")
-		return fmt.Errorf("location area is required")	
+		return fmt.Errorf("explore requires a location area, or goto one first")
 	}
 
-	fmt.Println("Exploring", location_area, "...")
+	config.Out.Println("Exploring", location_area, "...")
 
-	url := fmt.Sprintf("https://pokeapi.co/api/v2/location-area/%s", location_area)
-	location_area_pokemons, err := fetchLocationAreaPokemons(url, config)
+	location_area_pokemons, err := config.Client.LocationArea(ctx, location_area)
 	if err != nil {
 		return err
 	}
 
-	fmt.Println("Found Pokemon:")
+	config.Location = location_area
+
 	for _, pokemon := range location_area_pokemons.PokemonEncounters {
-		fmt.Println(" - ", pokemon.Pokemon.Name)
+		id := api.ResourceID(pokemon.Pokemon.URL)
+		if api.IsForm(pokemon.Pokemon.URL) {
+			form, err := config.Client.Pokemon(ctx, pokemon.Pokemon.Name)
+			if err != nil {
+				return err
+			}
+			id = form.SpeciesID()
+		}
+		config.MarkSeen(pokemon.Pokemon.Name, id)
+	}
+
+	if flags["summary"] != "" {
+		return config.Out.Render(summaryTable(Summarize(Encounters(location_area_pokemons, flags["version"], flags["method"]))))
+	}
+	if flags["version"] != "" || flags["method"] != "" {
+		return config.Out.Render(encounterTable(Encounters(location_area_pokemons, flags["version"], flags["method"])))
 	}
 
-	return nil
+	found := foundPokemon{Area: location_area, Pokemon: []string{}}
+	for _, pokemon := range location_area_pokemons.PokemonEncounters {
+		found.Pokemon = append(found.Pokemon, pokemon.Pokemon.Name)
+	}
+	return config.Out.Render(found)
 }
@@ -1,87 +1,194 @@
 package mappkg
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
 	"io"
-	"net/http"
+	"strings"
 
 	"github.com/almasx/pokedexcli/internal/api"
 	"github.com/almasx/pokedexcli/internal/cli"
 )
 
+type mapPage struct {
+	Areas    []string `json:"areas"`
+	Next     string   `json:"next"`
+	Previous string   `json:"previous"`
+}
 
-const BASE_URL = "https://pokeapi.co/api/v2/location-area/?offset=0&limit=20"
-
-func fetchMapData(url string, config *cli.Config) (api.GetLocationAreas, error) {
-	res := api.GetLocationAreas{}
-
-	if data, ok := config.Cache.Get(url); ok {
-		err := json.Unmarshal(data, &res)
-		if err != nil {
-			return res, err
-		}
-		return res, nil
+func (p mapPage) Text(w io.Writer) {
+	for _, area := range p.Areas {
+		fmt.Fprintln(w, area)
 	}
-	
-	data, err := http.Get(url)
-	if err != nil {
-		return api.GetLocationAreas{}, err
+}
+
+func (p mapPage) Table() ([]string, [][]string) {
+	rows := make([][]string, len(p.Areas))
+	for i, area := range p.Areas {
+		rows[i] = []string{area}
 	}
-	defer data.Body.Close()
-	body, err := io.ReadAll(data.Body)
-	if err != nil {
-		return api.GetLocationAreas{}, err
+	return []string{"area"}, rows
+}
+
+func newMapPage(config *cli.Config, mapData api.GetLocationAreas) mapPage {
+	page := mapPage{
+		Areas:    []string{},
+		Next:     mapData.Next,
+		Previous: mapData.Previous,
 	}
-	config.Cache.Add(url, body)
-	err = json.Unmarshal(body, &res)
-	if err != nil {
-		return api.GetLocationAreas{}, err
+	for _, result := range mapData.Results {
+		page.Areas = append(page.Areas, result.Name)
+		config.MarkArea(result.Name)
 	}
-	return res, nil
+	return page
 }
 
-func CommandMap(config *cli.Config, args []string) error {
-	mapData := api.GetLocationAreas{}
-	url := config.Next
-	if url == "" {
-		url = BASE_URL
+func CommandMap(ctx context.Context, config *cli.Config, args []string, flags map[string]string) error {
+	if flags["region"] != "" {
+		return printRegion(ctx, config, flags["region"])
 	}
-	
-	mapData, err := fetchMapData(url, config)
+
+	mapData, err := config.Client.LocationAreas(ctx, config.Next)
 	if err != nil {
 		return err
 	}
 
-	for _, result := range mapData.Results {
-		fmt.Println(result.Name)
-	}
-	
 	config.Next = mapData.Next
 	config.Prev = mapData.Previous
 
-	return nil
+	return config.Out.Render(newMapPage(config, mapData))
 }
 
-func CommandMapb(config *cli.Config, args []string) error {
-	mapData := api.GetLocationAreas{}
+func CommandMapb(ctx context.Context, config *cli.Config, args []string, flags map[string]string) error {
 	url := config.Prev
 	if url == "" {
-		fmt.Println("you're on the first page")
+		config.Out.Println("you're on the first page")
 		return nil
 	}
-	
-	mapData, err := fetchMapData(url, config)
+
+	mapData, err := config.Client.LocationAreas(ctx, url)
 	if err != nil {
 		return err
 	}
 
-	for _, result := range mapData.Results {
-		fmt.Println(result.Name)
-	}
-	
 	config.Prev = mapData.Previous
 	config.Next = mapData.Next
 
-	return nil
+	return config.Out.Render(newMapPage(config, mapData))
+}
+
+type regionLocation struct {
+	Name    string   `json:"name"`
+	Areas   []string `json:"areas"`
+	Current bool     `json:"current"`
+}
+
+type regionTree struct {
+	Region    string           `json:"region"`
+	Locations []regionLocation `json:"locations"`
+}
+
+func (t regionTree) Text(w io.Writer) {
+	fmt.Fprintln(w, t.Region)
+	for i, location := range t.Locations {
+		branch, indent := "├── ", "│   "
+		if i == len(t.Locations)-1 {
+			branch, indent = "└── ", "    "
+		}
+		marker := ""
+		if location.Current {
+			marker = " *"
+		}
+		fmt.Fprintf(w, "%v%v%v\n", branch, location.Name, marker)
+		for j, area := range location.Areas {
+			areaBranch := "├── "
+			if j == len(location.Areas)-1 {
+				areaBranch = "└── "
+			}
+			fmt.Fprintf(w, "%v%v%v\n", indent, areaBranch, area)
+		}
+	}
+}
+
+func (t regionTree) Table() ([]string, [][]string) {
+	var rows [][]string
+	for _, location := range t.Locations {
+		for _, area := range location.Areas {
+			rows = append(rows, []string{t.Region, location.Name, area})
+		}
+	}
+	return []string{"region", "location", "area"}, rows
+}
+
+// printRegion walks region -> location -> location-area and renders the tree.
+func printRegion(ctx context.Context, config *cli.Config, name string) error {
+	region, err := config.Client.Region(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	tree := regionTree{Region: region.Name, Locations: []regionLocation{}}
+	for _, l := range region.Locations {
+		location, err := config.Client.Location(ctx, l.Name)
+		if err != nil {
+			return err
+		}
+		entry := regionLocation{Name: location.Name, Areas: []string{}}
+		for _, area := range location.Areas {
+			entry.Areas = append(entry.Areas, area.Name)
+			config.MarkArea(area.Name)
+			if area.Name == config.Location {
+				entry.Current = true
+			}
+		}
+		tree.Locations = append(tree.Locations, entry)
+	}
+	return config.Out.Render(tree)
+}
+
+type position struct {
+	Location string `json:"location"`
+}
+
+func (p position) Text(w io.Writer) {
+	fmt.Fprintln(w, "You are now in", p.Location)
+}
+
+// CommandGoto moves the player to a location area. A location with a single
+// area resolves to that area.
+func CommandGoto(ctx context.Context, config *cli.Config, args []string, flags map[string]string) error {
+	name := args[0]
+
+	area := ""
+	_, err := config.Client.LocationArea(ctx, name)
+	switch {
+	case err == nil:
+		area = name
+	case !errors.Is(err, api.ErrNotFound):
+		return fmt.Errorf("goto %s: %w", name, err)
+	default:
+		location, err := config.Client.Location(ctx, name)
+		if errors.Is(err, api.ErrNotFound) {
+			return fmt.Errorf("unknown location: %s", name)
+		}
+		if err != nil {
+			return fmt.Errorf("goto %s: %w", name, err)
+		}
+		switch len(location.Areas) {
+		case 0:
+			return fmt.Errorf("%s has no areas to visit", name)
+		case 1:
+			area = location.Areas[0].Name
+		default:
+			var areas []string
+			for _, a := range location.Areas {
+				areas = append(areas, a.Name)
+			}
+			return fmt.Errorf("%s has several areas, pick one: %s", name, strings.Join(areas, ", "))
+		}
+	}
+
+	config.Location = area
+	return config.Out.Render(position{Location: area})
 }
@@ -0,0 +1,116 @@
+package mappkg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/almasx/pokedexcli/internal/api"
+	"github.com/almasx/pokedexcli/internal/apitest"
+	"github.com/almasx/pokedexcli/internal/cli"
+	"github.com/almasx/pokedexcli/internal/output"
+)
+
+// kanto has a location with one area, one with several, and one with none.
+var kanto = map[string]string{
+	"/region/kanto":                       `{"name": "kanto", "locations": [{"name": "pallet-town"}, {"name": "viridian-forest"}]}`,
+	"/location/pallet-town":               `{"name": "pallet-town", "areas": [{"name": "pallet-town-area"}]}`,
+	"/location/viridian-forest":           `{"name": "viridian-forest", "areas": [{"name": "viridian-forest-area"}, {"name": "viridian-forest-north"}]}`,
+	"/location/kanto-sea":                 `{"name": "kanto-sea", "areas": []}`,
+	"/location-area/pallet-town-area":     `{"name": "pallet-town-area"}`,
+	"/location-area/viridian-forest-area": `{"name": "viridian-forest-area"}`,
+}
+
+func testConfig(t *testing.T, handler http.HandlerFunc) (*cli.Config, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	return &cli.Config{
+		Client: apitest.NewClient(t, handler),
+		Out:    &output.Renderer{Format: output.Text, Out: out, Err: out},
+	}, out
+}
+
+func TestGoto(t *testing.T) {
+	cases := []struct {
+		name     string
+		location string
+		err      string
+	}{
+		{name: "viridian-forest-area", location: "viridian-forest-area"},
+		{name: "pallet-town", location: "pallet-town-area"},
+		{name: "viridian-forest", err: "viridian-forest has several areas, pick one: viridian-forest-area, viridian-forest-north"},
+		{name: "kanto-sea", err: "kanto-sea has no areas to visit"},
+		{name: "cinnabar-lab", err: "unknown location: cinnabar-lab"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			config, out := testConfig(t, apitest.Serve(kanto))
+			err := CommandGoto(context.Background(), config, []string{c.name}, nil)
+			if c.err != "" {
+				if err == nil || err.Error() != c.err {
+					t.Fatalf("expected error %q, got %v", c.err, err)
+				}
+				if config.Location != "" {
+					t.Errorf("expected to stay put, moved to %q", config.Location)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if config.Location != c.location {
+				t.Errorf("expected to be in %q, got %q", c.location, config.Location)
+			}
+			if !strings.Contains(out.String(), "You are now in "+c.location) {
+				t.Errorf("unexpected output %q", out.String())
+			}
+		})
+	}
+}
+
+func TestGotoKeepsErrors(t *testing.T) {
+	config, _ := testConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down", http.StatusInternalServerError)
+	})
+	err := CommandGoto(context.Background(), config, []string{"pallet-town"}, nil)
+	var status *api.StatusError
+	if !errors.As(err, &status) || status.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected the server error, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := CommandGoto(ctx, config, []string{"pallet-town"}, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestRegionTree(t *testing.T) {
+	config, out := testConfig(t, apitest.Serve(kanto))
+	config.Location = "viridian-forest-north"
+	if err := CommandMap(context.Background(), config, nil, map[string]string{"region": "kanto"}); err != nil {
+		t.Fatal(err)
+	}
+	expected := `kanto
+├── pallet-town
+│   └── pallet-town-area
+└── viridian-forest *
+    ├── viridian-forest-area
+    └── viridian-forest-north
+`
+	if out.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, out.String())
+	}
+}
+
+func TestMapbFirstPage(t *testing.T) {
+	config, out := testConfig(t, apitest.Serve(kanto))
+	if err := CommandMapb(context.Background(), config, nil, nil); err != nil {
+		t.Fatalf("expected no error on the first page, got %v", err)
+	}
+	if out.String() != "you're on the first page\n" {
+		t.Errorf("unexpected output %q", out.String())
+	}
+}
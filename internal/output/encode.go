@@ -0,0 +1,242 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// field is one key of a JSON object. Objects are decoded as []field rather
+// than maps so that YAML and CSV keep the struct field order.
+type field struct {
+	key   string
+	value any
+}
+
+// decode round-trips v through encoding/json into ordered generic values:
+// []field, []any, string, json.Number, bool or nil.
+func decode(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return decodeValue(dec)
+}
+
+func decodeValue(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		fields := []field{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, field{key.(string), value})
+		}
+		_, err := dec.Token()
+		return fields, err
+	case json.Delim('['):
+		items := []any{}
+		for dec.More() {
+			value, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, value)
+		}
+		_, err := dec.Token()
+		return items, err
+	}
+	return tok, nil
+}
+
+func scalar(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	data, _ := json.Marshal(plain(v))
+	return string(data)
+}
+
+// plain converts ordered values back into types encoding/json understands.
+func plain(v any) any {
+	switch v := v.(type) {
+	case []field:
+		m := make(map[string]any, len(v))
+		for _, f := range v {
+			m[f.key] = plain(f.value)
+		}
+		return m
+	case []any:
+		items := make([]any, len(v))
+		for i, item := range v {
+			items[i] = plain(item)
+		}
+		return items
+	}
+	return v
+}
+
+func writeYAML(w io.Writer, v any) error {
+	value, err := decode(v)
+	if err != nil {
+		return err
+	}
+	b := &strings.Builder{}
+	yamlValue(b, value, 0, false)
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+func yamlScalar(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		if v == "" || strings.ContainsAny(v, ":#{}[],&*!|>'\"%@`\n") || strings.TrimSpace(v) != v ||
+			v == "true" || v == "false" || v == "null" || v == "~" {
+			data, _ := json.Marshal(v)
+			return string(data)
+		}
+		if _, err := strconv.ParseFloat(v, 64); err == nil {
+			return strconv.Quote(v)
+		}
+		return v
+	}
+	return scalar(v)
+}
+
+// yamlValue writes v at the given indent. inline is set when v follows a
+// "- " or "key: " on the current line.
+func yamlValue(b *strings.Builder, v any, indent int, inline bool) {
+	pad := strings.Repeat("  ", indent)
+	switch v := v.(type) {
+	case []field:
+		if len(v) == 0 {
+			b.WriteString(" {}\n")
+			return
+		}
+		if inline {
+			b.WriteString("\n")
+		}
+		for _, f := range v {
+			b.WriteString(pad + yamlScalar(f.key) + ":")
+			yamlValue(b, f.value, indent+1, true)
+		}
+	case []any:
+		if len(v) == 0 {
+			b.WriteString(" []\n")
+			return
+		}
+		if inline {
+			b.WriteString("\n")
+		}
+		for _, item := range v {
+			b.WriteString(pad + "-")
+			if fields, ok := item.([]field); ok && len(fields) > 0 {
+				// Continue the first key on the dash line.
+				b.WriteString(" " + yamlScalar(fields[0].key) + ":")
+				yamlValue(b, fields[0].value, indent+2, true)
+				yamlValue(b, fields[1:], indent+1, false)
+				continue
+			}
+			yamlValue(b, item, indent+1, true)
+		}
+	default:
+		if inline {
+			b.WriteString(" ")
+		}
+		b.WriteString(yamlScalar(v) + "\n")
+	}
+}
+
+func writeCSV(w io.Writer, v any) error {
+	cw := csv.NewWriter(w)
+	if tabler, ok := v.(Tabler); ok {
+		header, rows := tabler.Table()
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(rows); err != nil {
+			return err
+		}
+		return cw.Error()
+	}
+
+	value, err := decode(v)
+	if err != nil {
+		return err
+	}
+	switch value := value.(type) {
+	case []any:
+		var header []string
+		index := map[string]int{}
+		for _, item := range value {
+			fields, ok := item.([]field)
+			if !ok {
+				fields = []field{{"value", item}}
+			}
+			for _, f := range fields {
+				if _, ok := index[f.key]; !ok {
+					index[f.key] = len(header)
+					header = append(header, f.key)
+				}
+			}
+		}
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+		for _, item := range value {
+			fields, ok := item.([]field)
+			if !ok {
+				fields = []field{{"value", item}}
+			}
+			row := make([]string, len(header))
+			for _, f := range fields {
+				row[index[f.key]] = scalar(f.value)
+			}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	case []field:
+		if err := cw.Write([]string{"field", "value"}); err != nil {
+			return err
+		}
+		for _, f := range value {
+			if err := cw.Write([]string{f.key, scalar(f.value)}); err != nil {
+				return err
+			}
+		}
+	default:
+		if err := cw.Write([]string{"value"}); err != nil {
+			return err
+		}
+		if err := cw.Write([]string{scalar(value)}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
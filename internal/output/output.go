@@ -0,0 +1,108 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+type Format string
+
+const (
+	Text Format = "text"
+	JSON Format = "json"
+	YAML Format = "yaml"
+	CSV  Format = "csv"
+)
+
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(name)); f {
+	case Text, JSON, YAML, CSV:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown output format %q (choose from text, json, yaml, csv)", name)
+}
+
+// Texter is implemented by results with a human-readable form.
+type Texter interface {
+	Text(w io.Writer)
+}
+
+// Tabler is implemented by results that are naturally a table, and controls
+// their CSV columns.
+type Tabler interface {
+	Table() (header []string, rows [][]string)
+}
+
+// Renderer formats command results. Narration such as battle logs and
+// progress goes to Log, which is stderr outside text mode so that stdout
+// only ever carries the rendered results.
+type Renderer struct {
+	Format Format
+	Out    io.Writer
+	Err    io.Writer
+}
+
+func NewRenderer(format Format) *Renderer {
+	return &Renderer{Format: format, Out: os.Stdout, Err: os.Stderr}
+}
+
+func (r *Renderer) Log() io.Writer {
+	if r.Format == Text || r.Format == "" {
+		return r.Out
+	}
+	return r.Err
+}
+
+// Printf writes narration to Log.
+func (r *Renderer) Printf(format string, args ...any) {
+	fmt.Fprintf(r.Log(), format, args...)
+}
+
+func (r *Renderer) Println(args ...any) {
+	fmt.Fprintln(r.Log(), args...)
+}
+
+func (r *Renderer) Render(v any) error {
+	switch r.Format {
+	case JSON:
+		enc := json.NewEncoder(r.Out)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		return enc.Encode(v)
+	case YAML:
+		return writeYAML(r.Out, v)
+	case CSV:
+		return writeCSV(r.Out, v)
+	}
+	if texter, ok := v.(Texter); ok {
+		texter.Text(r.Out)
+		return nil
+	}
+	_, err := fmt.Fprintln(r.Out, v)
+	return err
+}
+
+// Error reports a failed command in a way that suits the format.
+func (r *Renderer) Error(err error) {
+	if r.Format == Text || r.Format == "" {
+		fmt.Fprintln(r.Out, err)
+		return
+	}
+	fmt.Fprintln(r.Err, "error:", err)
+}
+
+// Message is the result of commands that only report what they did.
+type Message struct {
+	Message string `json:"message"`
+}
+
+func Messagef(format string, args ...any) Message {
+	return Message{Message: fmt.Sprintf(format, args...)}
+}
+
+func (m Message) Text(w io.Writer) {
+	fmt.Fprintln(w, m.Message)
+}
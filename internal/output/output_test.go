@@ -0,0 +1,64 @@
+package output
+
+import (
+	"strings"
+	"testing"
+)
+
+type stat struct {
+	Name string `json:"name"`
+	Base int    `json:"base"`
+}
+
+type inspectResult struct {
+	Name  string   `json:"name"`
+	Types []string `json:"types"`
+	Stats []stat   `json:"stats"`
+	Owned *int     `json:"owned"`
+}
+
+func render(t *testing.T, format Format, v any) string {
+	t.Helper()
+	out := &strings.Builder{}
+	r := &Renderer{Format: format, Out: out, Err: out}
+	if err := r.Render(v); err != nil {
+		t.Fatal(err)
+	}
+	return out.String()
+}
+
+func TestRender(t *testing.T) {
+	result := inspectResult{
+		Name:  "pikachu",
+		Types: []string{"electric"},
+		Stats: []stat{{"hp", 35}, {"speed", 90}},
+	}
+
+	cases := []struct {
+		format   Format
+		value    any
+		expected string
+	}{
+		{JSON, []string{"a"}, "[\n  \"a\"\n]\n"},
+		{YAML, result, `name: pikachu
+types:
+  - electric
+stats:
+  - name: hp
+    base: 35
+  - name: speed
+    base: 90
+owned: null
+`},
+		{YAML, map[string]string{"version": "1.0"}, "version: \"1.0\"\n"},
+		{CSV, result.Stats, "name,base\nhp,35\nspeed,90\n"},
+		{CSV, stat{"hp", 35}, "field,value\nname,hp\nbase,35\n"},
+	}
+
+	for _, c := range cases {
+		actual := render(t, c.format, c.value)
+		if actual != c.expected {
+			t.Errorf("%v: expected\n%v\ngot\n%v", c.format, c.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+const progressWidth = 30
+
+// Progress is a one-line progress bar redrawn in place. It only draws on a
+// terminal; piped or redirected output would just fill up with redraws.
+type Progress struct {
+	mu    sync.Mutex
+	w     io.Writer
+	label string
+	done  int
+	total int
+	live  bool
+}
+
+// Progress starts a bar on Log.
+func (r *Renderer) Progress(label string) *Progress {
+	w := r.Log()
+	return &Progress{w: w, label: label, live: isTerminal(w)}
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Grow adds n to the amount of work, which can keep growing as it is found.
+func (p *Progress) Grow(n int) {
+	p.mu.Lock()
+	p.total += n
+	p.draw()
+	p.mu.Unlock()
+}
+
+// Step marks one unit of work as done.
+func (p *Progress) Step() {
+	p.mu.Lock()
+	p.done++
+	p.draw()
+	p.mu.Unlock()
+}
+
+// Finish clears the bar so the next output starts on a clean line.
+func (p *Progress) Finish() {
+	p.mu.Lock()
+	if p.live {
+		fmt.Fprint(p.w, "\r\033[K")
+	}
+	p.mu.Unlock()
+}
+
+// draw redraws the bar. Callers hold p.mu.
+func (p *Progress) draw() {
+	if !p.live || p.total == 0 {
+		return
+	}
+	filled := p.done * progressWidth / p.total
+	fmt.Fprintf(p.w, "\r\033[K%v [%v%v] %v/%v", p.label,
+		strings.Repeat("#", filled), strings.Repeat("-", progressWidth-filled), p.done, p.total)
+}
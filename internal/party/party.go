@@ -0,0 +1,116 @@
+package party
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/almasx/pokedexcli/internal/cli"
+	"github.com/almasx/pokedexcli/internal/output"
+	"github.com/almasx/pokedexcli/internal/trainer"
+)
+
+type member struct {
+	ID       int    `json:"id"`
+	Nickname string `json:"nickname,omitempty"`
+	Species  string `json:"species"`
+	Level    int    `json:"level"`
+	Location string `json:"location,omitempty"`
+
+	owned *trainer.Owned
+}
+
+type memberList struct {
+	Title   string   `json:"-"`
+	Members []member `json:"members"`
+}
+
+func newMemberList(title string, list []*trainer.Owned) memberList {
+	members := make([]member, len(list))
+	for i, owned := range list {
+		members[i] = member{
+			ID:       owned.ID,
+			Nickname: owned.Nickname,
+			Species:  owned.Pokemon.Name,
+			Level:    owned.Level,
+			Location: owned.Location,
+			owned:    owned,
+		}
+	}
+	return memberList{Title: title, Members: members}
+}
+
+func (l memberList) Text(w io.Writer) {
+	if l.Title != "" {
+		fmt.Fprintln(w, l.Title)
+	}
+	for i, m := range l.Members {
+		fmt.Fprintf(w, "  %v. %v\n", i+1, m.owned)
+	}
+}
+
+func (l memberList) Table() ([]string, [][]string) {
+	rows := make([][]string, len(l.Members))
+	for i, m := range l.Members {
+		rows[i] = []string{fmt.Sprint(i + 1), fmt.Sprint(m.ID), m.Nickname, m.Species, fmt.Sprint(m.Level), m.Location}
+	}
+	return []string{"slot", "id", "nickname", "species", "level", "location"}, rows
+}
+
+func CommandParty(ctx context.Context, config *cli.Config, args []string, flags map[string]string) error {
+	title := fmt.Sprintf("Your party (%v/%v):", len(config.Collection.Party), trainer.PartySize)
+	return config.Out.Render(newMemberList(title, config.Collection.Party))
+}
+
+func CommandBox(ctx context.Context, config *cli.Config, args []string, flags map[string]string) error {
+	title := fmt.Sprintf("Your box (%v):", len(config.Collection.Box))
+	return config.Out.Render(newMemberList(title, config.Collection.Box))
+}
+
+func CommandDeposit(ctx context.Context, config *cli.Config, args []string, flags map[string]string) error {
+	owned, err := config.Collection.Deposit(args[0])
+	if err != nil {
+		return err
+	}
+	return config.Out.Render(output.Messagef("%v was sent to the box", owned.Name()))
+}
+
+func CommandWithdraw(ctx context.Context, config *cli.Config, args []string, flags map[string]string) error {
+	owned, err := config.Collection.Withdraw(args[0])
+	if err != nil {
+		return err
+	}
+	return config.Out.Render(output.Messagef("%v joined your party", owned.Name()))
+}
+
+func CommandSwap(ctx context.Context, config *cli.Config, args []string, flags map[string]string) error {
+	if err := config.Collection.Swap(args[0], args[1]); err != nil {
+		return err
+	}
+	return config.Out.Render(newMemberList("", config.Collection.Party))
+}
+
+func CommandNickname(ctx context.Context, config *cli.Config, args []string, flags map[string]string) error {
+	owned, err := config.Collection.Find(args[0])
+	if err != nil {
+		return err
+	}
+	if _, err := strconv.Atoi(strings.TrimPrefix(args[1], "#")); err == nil {
+		return fmt.Errorf("nickname can't be a number")
+	}
+	if other, err := config.Collection.Find(args[1]); err == nil && other != owned {
+		return fmt.Errorf("you already have a pokemon called %s", args[1])
+	}
+	owned.Nickname = args[1]
+	return config.Out.Render(output.Messagef("%v is now called %v", owned.Pokemon.Name, owned.Nickname))
+}
+
+func CommandRelease(ctx context.Context, config *cli.Config, args []string, flags map[string]string) error {
+	owned, err := config.Collection.Release(args[0])
+	if err != nil {
+		return err
+	}
+	return config.Out.Render(output.Messagef("%v was released. Bye, %v!", owned, owned.Name()))
+}
@@ -1,10 +1,34 @@
 package pokecache
 
 import (
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 )
 
+// fakeClock is a Clock that only moves when advanced.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *fakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	f.now = f.now.Add(d)
+	f.mu.Unlock()
+}
+
 type testCase struct {
 	cacheInterval time.Duration
 	sleepDuration time.Duration
@@ -12,37 +36,283 @@ type testCase struct {
 
 func TestCache(t *testing.T) {
 	cases := []struct {
-        input    testCase
-        expected bool
-    }{
-        {
-            input:    testCase{
+		input    testCase
+		expected bool
+	}{
+		{
+			input: testCase{
 				cacheInterval: time.Second * 2,
 				sleepDuration: time.Second * 1,
-				
 			},
 			expected: true,
-        },
+		},
 		{
-			input:    testCase{
+			input: testCase{
 				cacheInterval: time.Second * 1,
 				sleepDuration: time.Second * 2,
 			},
 			expected: false,
 		},
-    }
+	}
 
 	for _, c := range cases {
-		cache := NewCache(c.input.cacheInterval)
+		clock := newFakeClock()
+		cache := NewCacheWithClock(c.input.cacheInterval, clock.Now)
 		cache.Add("test", []byte("test"))
-		time.Sleep(c.input.sleepDuration)
+		clock.Advance(c.input.sleepDuration)
 		data, ok := cache.Get("test")
 		if ok != c.expected {
 			t.Errorf("expected data to be found: %v, got %v", c.expected, ok)
-		}	
+		}
 		if ok && string(data) != "test" {
 			t.Errorf("expected data to be 'test', got %v", data)
 		}
+		cache.Close()
+	}
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewCache(time.Minute)
+	defer cache.Close()
+	// Each entry is a one byte key plus a nine byte value.
+	cache.SetMaxBytes(30)
+	cache.Add("a", []byte("123456789"))
+	cache.Add("b", []byte("123456789"))
+	cache.Add("c", []byte("123456789"))
+	cache.Get("a")
+	cache.Add("d", []byte("123456789"))
+
+	for key, expected := range map[string]bool{"a": true, "b": false, "c": true, "d": true} {
+		if _, ok := cache.Get(key); ok != expected {
+			t.Errorf("expected %v cached: %v, got %v", key, expected, ok)
+		}
+	}
+
+	stats := cache.Stats()
+	if stats.Entries != 3 || stats.Bytes != 30 || stats.Evictions != 1 {
+		t.Errorf("expected 3 entries, 30 bytes and 1 eviction, got %+v", stats)
 	}
+	if stats.Hits != 4 || stats.Misses != 1 {
+		t.Errorf("expected 4 hits and 1 miss, got %+v", stats)
+	}
+}
 
-}
\ No newline at end of file
+func TestCacheBudget(t *testing.T) {
+	cache := NewCache(time.Minute)
+	defer cache.Close()
+	cache.SetMaxBytes(10)
+	cache.Add("big", make([]byte, 20))
+	if _, ok := cache.Get("big"); ok {
+		t.Errorf("expected an entry larger than the budget not to be cached")
+	}
+
+	cache.Add("a", []byte("1234"))
+	cache.Add("a", []byte("12"))
+	if stats := cache.Stats(); stats.Bytes != 3 || stats.Entries != 1 {
+		t.Errorf("expected replacing an entry to update its size, got %+v", stats)
+	}
+
+	cache.Add("b", []byte("12345"))
+	cache.SetMaxBytes(6)
+	if _, ok := cache.Get("a"); ok {
+		t.Errorf("expected shrinking the budget to evict the oldest entry")
+	}
+}
+
+func TestCacheExpiration(t *testing.T) {
+	clock := newFakeClock()
+	cache := NewCacheWithClock(time.Minute, clock.Now)
+	defer cache.Close()
+
+	cache.Add("a", []byte("1"))
+	clock.Advance(2 * time.Minute)
+	if _, ok := cache.Get("a"); ok {
+		t.Errorf("expected entry to expire")
+	}
+	if stats := cache.Stats(); stats.Expirations != 1 || stats.Entries != 0 {
+		t.Errorf("expected 1 expiration and no entries, got %+v", stats)
+	}
+}
+
+func TestCacheReap(t *testing.T) {
+	clock := newFakeClock()
+	cache := NewCacheWithClock(time.Minute, clock.Now)
+	defer cache.Close()
+
+	cache.Add("old", []byte("1"))
+	clock.Advance(45 * time.Second)
+	cache.Add("new", []byte("2"))
+	clock.Advance(30 * time.Second)
+	cache.reap()
+
+	stats := cache.Stats()
+	if stats.Entries != 1 || stats.Expirations != 1 || stats.Bytes != 4 {
+		t.Errorf("expected only the new entry to survive, got %+v", stats)
+	}
+	if _, ok := cache.Get("new"); !ok {
+		t.Errorf("expected new entry to survive the reap")
+	}
+}
+
+func TestCacheClose(t *testing.T) {
+	cache := NewCache(time.Millisecond)
+	cache.Add("a", []byte("1"))
+
+	closed := make(chan struct{})
+	go func() {
+		cache.Close()
+		cache.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("Close did not stop the reaper")
+	}
+
+	// The cache still works after Close, it just stops reaping.
+	cache.Add("b", []byte("2"))
+	if _, ok := cache.Get("b"); !ok {
+		t.Errorf("expected cache to stay usable after Close")
+	}
+}
+
+// TestCacheConcurrent is meant for go test -race: writers, readers and the
+// reaper all work on the same keys at once.
+func TestCacheConcurrent(t *testing.T) {
+	clock := newFakeClock()
+	cache := NewCacheWithClock(time.Millisecond, clock.Now)
+	defer cache.Close()
+	cache.SetMaxBytes(512)
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				key := fmt.Sprintf("key-%v", (w+i)%40)
+				switch i % 4 {
+				case 0:
+					cache.Add(key, []byte(key))
+				case 1:
+					if data, ok := cache.Get(key); ok && string(data) != key {
+						t.Errorf("expected %v, got %s", key, data)
+					}
+				case 2:
+					clock.Advance(time.Millisecond)
+					cache.reap()
+				case 3:
+					cache.Stats()
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if stats := cache.Stats(); stats.Bytes > 512 {
+		t.Errorf("expected the budget to hold under concurrency, got %+v", stats)
+	}
+}
+
+func TestCachePolicy(t *testing.T) {
+	clock := newFakeClock()
+	cache := NewCacheWithClock(time.Minute, clock.Now)
+	defer cache.Close()
+	cache.SetPolicy(func(key string) (TTL, bool) {
+		if key == "static" {
+			return TTL{Fresh: time.Hour, Stale: time.Hour}, true
+		}
+		return TTL{}, false
+	})
+
+	cache.Add("static", []byte("1"))
+	cache.Add("other", []byte("2"))
+	clock.Advance(2 * time.Minute)
+	if _, freshness := cache.Lookup("static"); freshness != Fresh {
+		t.Errorf("expected static entry to be fresh, got %v", freshness)
+	}
+	if _, freshness := cache.Lookup("other"); freshness != Missing {
+		t.Errorf("expected other entry to fall back to the interval, got %v", freshness)
+	}
+
+	clock.Advance(time.Hour)
+	if data, freshness := cache.Lookup("static"); freshness != Stale || string(data.Val) != "1" {
+		t.Errorf("expected static entry to be served stale, got %q %v", data.Val, freshness)
+	}
+	clock.Advance(time.Hour)
+	if _, ok := cache.Get("static"); ok {
+		t.Errorf("expected static entry to expire after its stale window")
+	}
+	if stats := cache.Stats(); stats.StaleHits != 1 {
+		t.Errorf("expected 1 stale hit, got %+v", stats)
+	}
+}
+
+func TestCacheKeepsRevalidatableEntries(t *testing.T) {
+	clock := newFakeClock()
+	cache := NewCacheWithClock(time.Minute, clock.Now)
+	defer cache.Close()
+
+	cache.AddEntry("a", Entry{Val: []byte("1"), ETag: `"v1"`})
+	clock.Advance(2 * time.Minute)
+	cache.reap()
+	entry, freshness := cache.Lookup("a")
+	if freshness != Expired || entry.ETag != `"v1"` {
+		t.Fatalf("expected expired entry to be kept for its ETag, got %+v %v", entry, freshness)
+	}
+	if _, ok := cache.Get("a"); ok {
+		t.Errorf("expected Get not to serve an expired entry")
+	}
+
+	cache.Revalidated("a", entry)
+	if data, ok := cache.Get("a"); !ok || string(data) != "1" {
+		t.Errorf("expected revalidated entry to be fresh, got %q %v", data, ok)
+	}
+	if stats := cache.Stats(); stats.Revalidations != 1 {
+		t.Errorf("expected 1 revalidation, got %+v", stats)
+	}
+}
+
+func TestCachePeek(t *testing.T) {
+	clock := newFakeClock()
+	cache := NewCacheWithClock(time.Minute, clock.Now)
+	defer cache.Close()
+	disk, err := NewDiskCache(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Each entry is a one byte key plus a nine byte value.
+	cache.SetMaxBytes(20)
+	cache.AddEntry("a", Entry{Val: []byte("123456789")})
+	cache.AddEntry("b", Entry{Val: []byte("123456789")})
+	cache.SetDisk(disk)
+	disk.AddEntry("c", Entry{Val: []byte("123456789"), StoredAt: clock.Now()})
+
+	before := cache.Stats()
+	for key, expected := range map[string]Freshness{"a": Fresh, "b": Fresh, "c": Fresh, "d": Missing} {
+		if freshness := cache.Peek(key); freshness != expected {
+			t.Errorf("expected %v to be %v, got %v", key, expected, freshness)
+		}
+	}
+	if after := cache.Stats(); after != before {
+		t.Errorf("expected Peek to leave stats alone, went from %+v to %+v", before, after)
+	}
+	if cache.Len() != 2 {
+		t.Errorf("expected Peek not to load c from disk, got %v entries", cache.Len())
+	}
+
+	// Peeking at a must not make it recently used, so adding c evicts it.
+	cache.AddEntry("c", Entry{Val: []byte("123456789")})
+	if cache.Peek("a") != Missing {
+		t.Errorf("expected a to be evicted as least recently used")
+	}
+
+	clock.Advance(2 * time.Minute)
+	if freshness := cache.Peek("b"); freshness != Missing {
+		t.Errorf("expected expired b to be missing, got %v", freshness)
+	}
+	if cache.Len() != 2 {
+		t.Errorf("expected Peek not to expire entries, got %v entries", cache.Len())
+	}
+}
@@ -0,0 +1,246 @@
+package pokecache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// DiskCache stores one file per key under dir, trimmed to maxBytes oldest
+// first. It does not expire entries itself: the Cache in front of it drops
+// them by the same policy as its memory tier.
+type DiskCache struct {
+	mu       sync.Mutex
+	dir      string
+	maxBytes int64
+	// bytes is a running total of the entry files, so that writes only scan
+	// the directory once it grows past maxBytes. It is -1 until first needed.
+	bytes int64
+}
+
+type DiskStats struct {
+	Dir     string
+	Entries int
+	Bytes   int64
+}
+
+func NewDiskCache(dir string, maxBytes int64) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &DiskCache{
+		dir:      dir,
+		maxBytes: maxBytes,
+		bytes:    -1,
+	}, nil
+}
+
+func (d *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:]))
+}
+
+const etagExt = ".etag"
+
+// etagPath is the sidecar file holding the ETag of the entry at path.
+func etagPath(path string) string {
+	return path + etagExt
+}
+
+func (d *DiskCache) Get(key string) ([]byte, bool) {
+	entry, ok := d.GetEntry(key)
+	return entry.Val, ok
+}
+
+// GetEntry returns the entry for key, stamped with the time it was written.
+func (d *DiskCache) GetEntry(key string) (Entry, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	path := d.path(key)
+	info, err := os.Stat(path)
+	if err != nil {
+		return Entry{}, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Entry{}, false
+	}
+	// A missing sidecar just means there is nothing to revalidate with.
+	etag, _ := os.ReadFile(etagPath(path))
+	return Entry{Val: data, ETag: string(etag), StoredAt: info.ModTime()}, true
+}
+
+// peek is GetEntry without reading the value.
+func (d *DiskCache) peek(key string) (Entry, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	path := d.path(key)
+	info, err := os.Stat(path)
+	if err != nil {
+		return Entry{}, false
+	}
+	etag, _ := os.ReadFile(etagPath(path))
+	return Entry{ETag: string(etag), StoredAt: info.ModTime()}, true
+}
+
+// Remove deletes the entry for key, if there is one.
+func (d *DiskCache) Remove(key string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	path := d.path(key)
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+	if err := d.remove(filepath.Base(path)); err != nil {
+		return err
+	}
+	if d.bytes >= 0 {
+		d.bytes -= info.Size()
+	}
+	return nil
+}
+
+func (d *DiskCache) Add(key string, val []byte) error {
+	return d.AddEntry(key, Entry{Val: val})
+}
+
+// AddEntry writes entry under key. Its ETag, if any, is kept in a sidecar
+// file so entries written before ETags were stored still read back.
+func (d *DiskCache) AddEntry(key string, entry Entry) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	path := d.path(key)
+	if entry.ETag == "" {
+		os.Remove(etagPath(path))
+	} else if err := os.WriteFile(etagPath(path), []byte(entry.ETag), 0o644); err != nil {
+		return err
+	}
+	var replaced int64
+	if info, err := os.Stat(path); err == nil {
+		replaced = info.Size()
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, entry.Val, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	if d.maxBytes <= 0 {
+		return nil
+	}
+	if d.bytes < 0 {
+		_, total, err := d.files()
+		if err != nil {
+			return err
+		}
+		d.bytes = total
+	} else {
+		d.bytes += int64(len(entry.Val)) - replaced
+	}
+	if d.bytes > d.maxBytes {
+		return d.trim()
+	}
+	return nil
+}
+
+// trim rescans the directory and removes the oldest files until it fits in
+// maxBytes. The caller must hold d.mu.
+func (d *DiskCache) trim() error {
+	files, total, err := d.files()
+	if err != nil {
+		return err
+	}
+	defer func() { d.bytes = total }()
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].ModTime().Before(files[j].ModTime())
+	})
+	for _, file := range files {
+		if total <= d.maxBytes {
+			break
+		}
+		if err := d.remove(file.Name()); err != nil {
+			return err
+		}
+		total -= file.Size()
+	}
+	return nil
+}
+
+// remove deletes an entry file and its ETag sidecar. The caller must hold d.mu.
+func (d *DiskCache) remove(name string) error {
+	path := filepath.Join(d.dir, name)
+	for _, p := range []string{path, etagPath(path)} {
+		if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *DiskCache) files() ([]fs.FileInfo, int64, error) {
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		return nil, 0, err
+	}
+	var files []fs.FileInfo
+	var total int64
+	for _, entry := range entries {
+		// Entries are bare hashes; skip .tmp files and .etag sidecars.
+		if !entry.Type().IsRegular() || filepath.Ext(entry.Name()) != "" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, info)
+		total += info.Size()
+	}
+	return files, total, nil
+}
+
+func (d *DiskCache) Clear() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	files, _, err := d.files()
+	if err != nil {
+		return err
+	}
+	d.bytes = -1
+	for _, file := range files {
+		if err := d.remove(file.Name()); err != nil {
+			return err
+		}
+	}
+	d.bytes = 0
+	return nil
+}
+
+func (d *DiskCache) Stats() (DiskStats, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	files, total, err := d.files()
+	if err != nil {
+		return DiskStats{}, err
+	}
+	return DiskStats{
+		Dir:     d.dir,
+		Entries: len(files),
+		Bytes:   total,
+	}, nil
+}
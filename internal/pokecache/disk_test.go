@@ -0,0 +1,166 @@
+package pokecache
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestDiskCache(t *testing.T) {
+	disk, err := NewDiskCache(t.TempDir(), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache := NewCache(time.Minute)
+	cache.SetDisk(disk)
+	cache.Add("test", []byte("test"))
+
+	fresh := NewCache(time.Minute)
+	fresh.SetDisk(disk)
+	data, ok := fresh.Get("test")
+	if !ok || string(data) != "test" {
+		t.Errorf("expected 'test' from disk, got %v %v", string(data), ok)
+	}
+
+	disk.Add("a", []byte("aaaaaa"))
+	time.Sleep(10 * time.Millisecond)
+	disk.Add("b", []byte("bbbbbb"))
+	if _, ok := disk.Get("a"); ok {
+		t.Errorf("expected oldest entry to be trimmed over the size cap")
+	}
+	if _, ok := disk.Get("b"); !ok {
+		t.Errorf("expected newest entry to be kept")
+	}
+
+	if err := fresh.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	stats, err := disk.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Entries != 0 || fresh.Len() != 0 {
+		t.Errorf("expected empty cache after Clear, got %v on disk and %v in memory", stats.Entries, fresh.Len())
+	}
+}
+
+func TestDiskCacheETag(t *testing.T) {
+	disk, err := NewDiskCache(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	disk.AddEntry("a", Entry{Val: []byte("1"), ETag: `"v1"`})
+	entry, ok := disk.GetEntry("a")
+	if !ok || entry.ETag != `"v1"` || entry.StoredAt.IsZero() {
+		t.Errorf("expected entry with its ETag, got %+v %v", entry, ok)
+	}
+
+	disk.Add("a", []byte("2"))
+	if entry, _ := disk.GetEntry("a"); entry.ETag != "" {
+		t.Errorf("expected overwriting without an ETag to drop the old one, got %q", entry.ETag)
+	}
+
+	disk.AddEntry("b", Entry{Val: []byte("3"), ETag: `"v3"`})
+	if stats, _ := disk.Stats(); stats.Entries != 2 {
+		t.Errorf("expected ETag sidecars not to count as entries, got %v", stats.Entries)
+	}
+	if err := disk.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if files, _ := os.ReadDir(disk.dir); len(files) != 0 {
+		t.Errorf("expected Clear to remove sidecars, %v files left", len(files))
+	}
+}
+
+func TestDiskCacheRunningTotal(t *testing.T) {
+	disk, err := NewDiskCache(t.TempDir(), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	disk.Add("a", []byte("1234"))
+	disk.Add("b", []byte("12345678"))
+	time.Sleep(10 * time.Millisecond)
+	disk.Add("a", []byte("12"))
+	stats, err := disk.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if disk.bytes != stats.Bytes || stats.Bytes != 10 {
+		t.Errorf("expected running total to match the directory's 10 bytes, got %v and %v", disk.bytes, stats.Bytes)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	disk.Add("c", make([]byte, 95))
+	if _, ok := disk.Get("b"); ok {
+		t.Errorf("expected crossing the budget to trim the oldest entry")
+	}
+	if stats, _ := disk.Stats(); disk.bytes != stats.Bytes {
+		t.Errorf("expected trim to resync the running total, got %v, directory has %v", disk.bytes, stats.Bytes)
+	}
+}
+
+func TestDiskCacheWriteErrors(t *testing.T) {
+	dir := t.TempDir()
+	disk, err := NewDiskCache(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := NewCache(time.Minute)
+	defer cache.Close()
+	cache.SetDisk(disk)
+
+	os.RemoveAll(dir)
+	cache.Add("a", []byte("1"))
+	if stats := cache.Stats(); stats.DiskErrors != 1 {
+		t.Errorf("expected the failed disk write to be counted, got %+v", stats)
+	}
+	if _, ok := cache.Get("a"); !ok {
+		t.Errorf("expected the memory tier to keep the entry")
+	}
+}
+
+func TestDiskCacheFollowsPolicy(t *testing.T) {
+	disk, err := NewDiskCache(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const day = 24 * time.Hour
+	policy := func(key string) (TTL, bool) {
+		return TTL{Fresh: 60 * day, Stale: 30 * day}, true
+	}
+	cache := NewCache(time.Minute)
+	defer cache.Close()
+	cache.SetPolicy(policy)
+	cache.SetDisk(disk)
+	cache.AddEntry("plain", Entry{Val: []byte("1")})
+	cache.AddEntry("tagged", Entry{Val: []byte("2"), ETag: `"v2"`})
+
+	// later is a new session with an empty memory tier, some days on.
+	later := func(days time.Duration) *Cache {
+		c := NewCacheWithClock(time.Minute, func() time.Time { return time.Now().Add(days * day) })
+		c.SetPolicy(policy)
+		c.SetDisk(disk)
+		t.Cleanup(c.Close)
+		return c
+	}
+	if _, freshness := later(45).Lookup("plain"); freshness != Fresh {
+		t.Errorf("expected a 60 day policy to outlive a restart after 45 days, got %v", freshness)
+	}
+	if _, freshness := later(75).Lookup("plain"); freshness != Stale {
+		t.Errorf("expected the entry to be stale after 75 days, got %v", freshness)
+	}
+
+	expired := later(120)
+	if _, freshness := expired.Lookup("plain"); freshness != Missing {
+		t.Errorf("expected the entry without an ETag to expire, got %v", freshness)
+	}
+	if entry, freshness := expired.Lookup("tagged"); freshness != Expired || entry.ETag != `"v2"` {
+		t.Errorf("expected the expired entry to be kept for its ETag, got %+v %v", entry, freshness)
+	}
+	if stats, _ := disk.Stats(); stats.Entries != 1 {
+		t.Errorf("expected only the expired entry without an ETag to leave the disk, got %v entries", stats.Entries)
+	}
+}
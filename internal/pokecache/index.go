@@ -1,57 +1,391 @@
 package pokecache
 
 import (
+	"container/list"
 	"sync"
 	"time"
 )
 
+// DefaultMaxBytes is the memory budget of a new cache.
+const DefaultMaxBytes = 32 << 20
+
+// Entry is a cached value and what is known about where it came from.
+type Entry struct {
+	Val []byte
+	// ETag is the validator the server sent with Val, if any.
+	ETag string
+	// StoredAt is when Val was fetched or last revalidated.
+	StoredAt time.Time
+}
+
+// TTL says how long an entry is fresh and for how long after that it may
+// still be served stale while a newer copy is fetched.
+type TTL struct {
+	Fresh time.Duration
+	Stale time.Duration
+}
+
+// Policy picks the TTL of an entry from its key. Keys it has no TTL for
+// report false and get the cache's default.
+type Policy func(key string) (TTL, bool)
+
+// Freshness is how usable a looked up entry is.
+type Freshness int
+
+const (
+	// Missing means there is no entry.
+	Missing Freshness = iota
+	// Fresh entries can be served as they are.
+	Fresh
+	// Stale entries can be served, but should be refreshed.
+	Stale
+	// Expired entries must not be served without revalidating them first.
+	Expired
+)
+
 type cacheEntry struct {
-	val []byte
-	createdAt time.Time
+	Entry
+	key string
+	ttl TTL
+}
+
+// size is what an entry counts against the memory budget.
+func (e *cacheEntry) size() int64 {
+	return int64(len(e.key) + len(e.Val) + len(e.ETag))
 }
 
+func (e *cacheEntry) freshness(now time.Time) Freshness {
+	age := now.Sub(e.StoredAt)
+	switch {
+	case age <= e.ttl.Fresh:
+		return Fresh
+	case age <= e.ttl.Fresh+e.ttl.Stale:
+		return Stale
+	}
+	return Expired
+}
+
+// Stats is a snapshot of the memory tier's size and counters.
+type Stats struct {
+	Entries       int    `json:"entries"`
+	Bytes         int64  `json:"bytes"`
+	MaxBytes      int64  `json:"max_bytes"`
+	Hits          uint64 `json:"hits"`
+	StaleHits     uint64 `json:"stale_hits"`
+	Misses        uint64 `json:"misses"`
+	DiskHits      uint64 `json:"disk_hits"`
+	Revalidations uint64 `json:"revalidations"`
+	Evictions     uint64 `json:"evictions"`
+	Expirations   uint64 `json:"expirations"`
+	// DiskErrors counts writes to the disk tier that failed.
+	DiskErrors uint64 `json:"disk_errors"`
+}
+
+// Clock reports the current time. Caches use time.Now; tests pass a fake so
+// expiry can be checked without sleeping.
+type Clock func() time.Time
+
+// Cache is an in-memory LRU cache with a byte budget and per-key TTLs,
+// optionally backed by a DiskCache.
 type Cache struct {
-	mu sync.RWMutex
-	entries map[string]cacheEntry
+	mu      sync.RWMutex
+	entries map[string]*list.Element
+	// lru orders entries from most (front) to least (back) recently used.
+	lru      *list.List
+	bytes    int64
+	maxBytes int64
 	interval time.Duration
+	policy   Policy
+	disk     *DiskCache
+	stats    Stats
+	now      Clock
+
+	// done stops the reaper; closeOnce guards it and stopped is closed
+	// once the reaper has returned.
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
 }
 
+// NewCache returns a cache whose entries stay fresh for interval unless a
+// Policy says otherwise. The reaper runs every interval.
 func NewCache(interval time.Duration) *Cache {
+	return NewCacheWithClock(interval, time.Now)
+}
+
+// NewCacheWithClock is NewCache with entry ages measured by now. The reaper
+// still wakes on a real ticker every interval.
+func NewCacheWithClock(interval time.Duration, now Clock) *Cache {
 	c := &Cache{
-		entries: make(map[string]cacheEntry),
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+		maxBytes: DefaultMaxBytes,
 		interval: interval,
+		now:      now,
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
 	}
 	go c.readLoop()
 
 	return c
+}
+
+// Close stops the reaper goroutine and waits for it to exit. The cache stays
+// usable; entries just no longer expire in the background.
+func (c *Cache) Close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+	<-c.stopped
+}
 
+// SetDisk installs a persistent tier that Get falls back to and Add writes through to.
+func (c *Cache) SetDisk(disk *DiskCache) {
+	c.mu.Lock()
+	c.disk = disk
+	c.mu.Unlock()
+}
+
+func (c *Cache) Disk() *DiskCache {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.disk
+}
+
+// SetPolicy installs the TTLs for entries added from now on. By default an
+// entry is fresh for the cache's interval and never stale.
+func (c *Cache) SetPolicy(policy Policy) {
+	c.mu.Lock()
+	c.policy = policy
+	c.mu.Unlock()
+}
+
+func (c *Cache) ttl(key string) TTL {
+	if c.policy != nil {
+		if ttl, ok := c.policy(key); ok {
+			return ttl
+		}
+	}
+	return TTL{Fresh: c.interval}
+}
+
+// SetMaxBytes changes the memory budget, evicting entries if it shrank.
+func (c *Cache) SetMaxBytes(maxBytes int64) {
+	c.mu.Lock()
+	c.maxBytes = maxBytes
+	c.evict()
+	c.mu.Unlock()
 }
 
 func (c *Cache) Add(key string, val []byte) {
+	c.AddEntry(key, Entry{Val: val})
+}
+
+// AddEntry stores entry under key, stamping it with the current time unless
+// StoredAt is already set.
+func (c *Cache) AddEntry(key string, entry Entry) {
 	c.mu.Lock()
+	if entry.StoredAt.IsZero() {
+		entry.StoredAt = c.now()
+	}
+	c.add(key, entry)
+	disk := c.disk
+	c.mu.Unlock()
 
-	c.entries[key] = cacheEntry{
-		val: val,
-		createdAt: time.Now(),
+	if disk != nil {
+		if err := disk.AddEntry(key, entry); err != nil {
+			c.mu.Lock()
+			c.stats.DiskErrors++
+			c.mu.Unlock()
+		}
 	}
+}
+
+// Revalidated stores entry again after the server confirmed it is unchanged,
+// making it fresh for another TTL.
+func (c *Cache) Revalidated(key string, entry Entry) {
+	entry.StoredAt = time.Time{}
+	c.AddEntry(key, entry)
+	c.mu.Lock()
+	c.stats.Revalidations++
 	c.mu.Unlock()
 }
 
+// add stores an entry as the most recently used. Callers hold c.mu.
+func (c *Cache) add(key string, entry Entry) {
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+	ce := &cacheEntry{Entry: entry, key: key, ttl: c.ttl(key)}
+	if ce.size() > c.maxBytes {
+		// It would evict everything else and still not fit.
+		return
+	}
+	c.entries[key] = c.lru.PushFront(ce)
+	c.bytes += ce.size()
+	c.evict()
+}
+
+// evict drops least recently used entries until the cache fits its budget.
+// Callers hold c.mu.
+func (c *Cache) evict() {
+	for c.bytes > c.maxBytes {
+		c.remove(c.lru.Back())
+		c.stats.Evictions++
+	}
+}
+
+// remove unlinks an entry. Callers hold c.mu.
+func (c *Cache) remove(elem *list.Element) {
+	entry := c.lru.Remove(elem).(*cacheEntry)
+	delete(c.entries, entry.key)
+	c.bytes -= entry.size()
+}
+
+// dead reports whether an entry is no use any more: expired, and without an
+// ETag it could be cheaply revalidated with.
+func (c *Cache) dead(entry *cacheEntry) bool {
+	return entry.ETag == "" && entry.freshness(c.now()) == Expired
+}
+
+// Get returns the value for key if it is fresh or stale.
 func (c *Cache) Get(key string) ([]byte, bool) {
+	entry, freshness := c.Lookup(key)
+	if freshness != Fresh && freshness != Stale {
+		return nil, false
+	}
+	return entry.Val, true
+}
+
+// Lookup returns the entry for key from memory or disk along with how fresh
+// it is. Expired entries are only kept, and returned, while they have an
+// ETag to revalidate them with.
+func (c *Cache) Lookup(key string) (Entry, Freshness) {
+	c.mu.Lock()
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		switch freshness := entry.freshness(c.now()); {
+		case freshness == Fresh || freshness == Stale:
+			c.lru.MoveToFront(elem)
+			c.stats.Hits++
+			if freshness == Stale {
+				c.stats.StaleHits++
+			}
+			c.mu.Unlock()
+			return entry.Entry, freshness
+		case entry.ETag != "":
+			c.stats.Misses++
+			c.mu.Unlock()
+			return entry.Entry, Expired
+		}
+		c.remove(elem)
+		c.stats.Expirations++
+	}
+	c.stats.Misses++
+	disk := c.disk
+	c.mu.Unlock()
+	if disk == nil {
+		return Entry{}, Missing
+	}
+
+	entry, ok := disk.GetEntry(key)
+	if !ok {
+		return Entry{}, Missing
+	}
+	c.mu.Lock()
+	ce := &cacheEntry{Entry: entry, key: key, ttl: c.ttl(key)}
+	if c.dead(ce) {
+		c.stats.Expirations++
+		c.mu.Unlock()
+		disk.Remove(key)
+		return Entry{}, Missing
+	}
+	defer c.mu.Unlock()
+	c.stats.DiskHits++
+	c.add(key, entry)
+	return entry, ce.freshness(c.now())
+}
+
+// Peek reports how fresh the entry for key is, like Lookup, but without
+// counting a hit or miss, reordering the LRU, expiring entries or loading
+// them from disk.
+func (c *Cache) Peek(key string) Freshness {
 	c.mu.RLock()
-	data, ok := c.entries[key]
-    c.mu.RUnlock()
-	return data.val, ok
+	var ce *cacheEntry
+	if elem, ok := c.entries[key]; ok {
+		ce = elem.Value.(*cacheEntry)
+	}
+	disk, ttl := c.disk, c.ttl(key)
+	c.mu.RUnlock()
+
+	if ce == nil && disk != nil {
+		if entry, ok := disk.peek(key); ok {
+			ce = &cacheEntry{Entry: entry, key: key, ttl: ttl}
+		}
+	}
+	if ce == nil || c.dead(ce) {
+		return Missing
+	}
+	return ce.freshness(c.now())
+}
+
+func (c *Cache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.entries)
+}
+
+// Stats returns the current size and counters of the memory tier.
+func (c *Cache) Stats() Stats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	stats := c.stats
+	stats.Entries = len(c.entries)
+	stats.Bytes = c.bytes
+	stats.MaxBytes = c.maxBytes
+	return stats
+}
+
+// Clear drops every entry from memory and, if present, the disk tier.
+func (c *Cache) Clear() error {
+	c.mu.Lock()
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+	c.bytes = 0
+	disk := c.disk
+	c.mu.Unlock()
+
+	if disk != nil {
+		return disk.Clear()
+	}
+	return nil
 }
 
 func (c *Cache) readLoop() {
+	defer close(c.stopped)
 	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
 
-    for range ticker.C {
-		for key, entry := range c.entries {
-			if time.Since(entry.createdAt) > c.interval {
-				delete(c.entries, key)
-			}
+	for {
+		select {
+		case <-ticker.C:
+			c.reap()
+		case <-c.done:
+			return
 		}
 	}
-}
\ No newline at end of file
+}
+
+// reap drops every dead entry.
+func (c *Cache) reap() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for elem := c.lru.Back(); elem != nil; {
+		prev := elem.Prev()
+		if c.dead(elem.Value.(*cacheEntry)) {
+			c.remove(elem)
+			c.stats.Expirations++
+		}
+		elem = prev
+	}
+}
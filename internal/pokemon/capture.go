@@ -0,0 +1,146 @@
+package pokemon
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/almasx/pokedexcli/internal/api"
+)
+
+type Status string
+
+const (
+	StatusNone      Status = ""
+	StatusSleep     Status = "sleep"
+	StatusFreeze    Status = "freeze"
+	StatusParalysis Status = "paralysis"
+	StatusPoison    Status = "poison"
+	StatusBurn      Status = "burn"
+)
+
+var statuses = []Status{StatusSleep, StatusFreeze, StatusParalysis, StatusPoison, StatusBurn}
+
+func ParseStatus(name string) (Status, error) {
+	name = strings.ToLower(name)
+	if name == "" || name == "none" {
+		return StatusNone, nil
+	}
+	for _, status := range statuses {
+		if Status(name) == status {
+			return status, nil
+		}
+	}
+	names := make([]string, len(statuses))
+	for i, status := range statuses {
+		names[i] = string(status)
+	}
+	return StatusNone, fmt.Errorf("unknown status %q (choose from %s)", name, strings.Join(names, ", "))
+}
+
+func (s Status) multiplier() float64 {
+	switch s {
+	case StatusSleep, StatusFreeze:
+		return 2
+	case StatusParalysis, StatusPoison, StatusBurn:
+		return 1.5
+	}
+	return 1
+}
+
+type Ball struct {
+	Name       string
+	Multiplier float64
+	// Guaranteed balls skip the capture roll entirely.
+	Guaranteed bool
+}
+
+var balls = map[string]Ball{
+	"poke":   {Name: "Poke Ball", Multiplier: 1},
+	"great":  {Name: "Great Ball", Multiplier: 1.5},
+	"ultra":  {Name: "Ultra Ball", Multiplier: 2},
+	"master": {Name: "Master Ball", Multiplier: 255, Guaranteed: true},
+}
+
+func LookupBall(name string) (Ball, error) {
+	name = strings.TrimSuffix(strings.ToLower(name), "ball")
+	name = strings.TrimRight(name, "-_ ")
+	if name == "" {
+		name = "poke"
+	}
+	ball, ok := balls[name]
+	if !ok {
+		var names []string
+		for name := range balls {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return Ball{}, fmt.Errorf("unknown ball %q (choose from %s)", name, strings.Join(names, ", "))
+	}
+	return ball, nil
+}
+
+// Wild is the state of a wild pokemon at the moment a ball is thrown.
+type Wild struct {
+	Level  int
+	MaxHP  int
+	HP     int
+	Status Status
+}
+
+// DefaultWildLevel is the level wild pokemon are met, and therefore caught, at.
+const DefaultWildLevel = 50
+
+// HPStat computes a pokemon's max HP at level with neutral IVs and EVs.
+func HPStat(pokemon api.GetPokemon, level int) int {
+	base := 0
+	for _, stat := range pokemon.Stats {
+		if stat.Stat.Name == "hp" {
+			base = stat.BaseStat
+		}
+	}
+	return (2*base+15)*level/100 + level + 10
+}
+
+// NewWild returns pokemon at level, at full health and without a status.
+func NewWild(pokemon api.GetPokemon, level int) Wild {
+	hp := HPStat(pokemon, level)
+	return Wild{Level: level, MaxHP: hp, HP: hp}
+}
+
+// CaptureResult reports how many times the ball shook; four shakes is a catch.
+type CaptureResult struct {
+	Caught bool
+	Shakes int
+}
+
+// Capture applies the generation III/IV catch formula. intn must return a
+// uniformly random integer in [0, n).
+func Capture(captureRate int, wild Wild, ball Ball, intn func(int) int) CaptureResult {
+	if ball.Guaranteed {
+		return CaptureResult{Caught: true, Shakes: 4}
+	}
+	maxHP := max(wild.MaxHP, 1)
+	hp := min(max(wild.HP, 1), maxHP)
+
+	a := math.Floor(float64((3*maxHP-2*hp)*captureRate) * ball.Multiplier / float64(3*maxHP))
+	a = math.Floor(a * wild.Status.multiplier())
+	if a >= 255 {
+		return CaptureResult{Caught: true, Shakes: 4}
+	}
+	if a <= 0 {
+		return CaptureResult{}
+	}
+
+	b := int(1048560 / math.Sqrt(math.Sqrt(16711680/a)))
+	result := CaptureResult{}
+	for result.Shakes < 4 {
+		if intn(65536) >= b {
+			return result
+		}
+		result.Shakes++
+	}
+	result.Caught = true
+	return result
+}
@@ -0,0 +1,43 @@
+package pokemon
+
+import "testing"
+
+func TestCapture(t *testing.T) {
+	always := func(n int) int { return 0 }
+	never := func(n int) int { return n - 1 }
+	wild := Wild{Level: 50, MaxHP: 100, HP: 100}
+
+	cases := []struct {
+		name     string
+		rate     int
+		wild     Wild
+		ball     Ball
+		intn     func(int) int
+		expected CaptureResult
+	}{
+		{"master ball", 3, wild, balls["master"], never, CaptureResult{Caught: true, Shakes: 4}},
+		{"asleep at 1 hp", 255, Wild{MaxHP: 100, HP: 1, Status: StatusSleep}, balls["poke"], never, CaptureResult{Caught: true, Shakes: 4}},
+		{"lucky rolls", 45, wild, balls["poke"], always, CaptureResult{Caught: true, Shakes: 4}},
+		{"unlucky rolls", 45, wild, balls["poke"], never, CaptureResult{Caught: false, Shakes: 0}},
+		{"zero capture rate", 0, wild, balls["ultra"], always, CaptureResult{Caught: false, Shakes: 0}},
+	}
+
+	for _, c := range cases {
+		actual := Capture(c.rate, c.wild, c.ball, c.intn)
+		if actual != c.expected {
+			t.Errorf("%s: expected %+v, got %+v", c.name, c.expected, actual)
+		}
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	cases := map[string]Status{"": StatusNone, "none": StatusNone, "Sleep": StatusSleep, "burn": StatusBurn}
+	for name, expected := range cases {
+		if actual, err := ParseStatus(name); err != nil || actual != expected {
+			t.Errorf("ParseStatus(%q) == %q, %v, expected %q", name, actual, err, expected)
+		}
+	}
+	if _, err := ParseStatus("confused"); err == nil {
+		t.Errorf("expected an unknown status to be rejected")
+	}
+}
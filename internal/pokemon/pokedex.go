@@ -0,0 +1,300 @@
+package pokemon
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/almasx/pokedexcli/internal/api"
+	"github.com/almasx/pokedexcli/internal/cli"
+)
+
+type dexEntry struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Seen   bool   `json:"seen"`
+	Caught bool   `json:"caught"`
+}
+
+// dexEntries merges seen and caught pokemon into one entry per species,
+// ordered by national dex number. A species caught in any form counts as
+// caught, and is listed under the name of the form that was caught.
+func dexEntries(config *cli.Config) []dexEntry {
+	byName := map[string]*dexEntry{}
+	for name, id := range config.Seen {
+		byName[name] = &dexEntry{ID: id, Name: name, Seen: true}
+	}
+	for name, pokemon := range config.Pokedex {
+		byName[name] = &dexEntry{ID: pokemon.SpeciesID(), Name: name, Seen: true, Caught: true}
+	}
+
+	entries := make([]dexEntry, 0, len(byName))
+	bySpecies := map[int]int{}
+	for _, entry := range byName {
+		// Without a dex number there is nothing to merge on.
+		if i, ok := bySpecies[entry.ID]; ok && entry.ID != 0 {
+			if preferEntry(*entry, entries[i]) {
+				entries[i] = *entry
+			}
+			continue
+		}
+		bySpecies[entry.ID] = len(entries)
+		entries = append(entries, *entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].ID != entries[j].ID {
+			return entries[i].ID < entries[j].ID
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	return entries
+}
+
+// preferEntry reports whether a should stand for its species instead of b:
+// caught forms win, then the shortest name, which is the base form.
+func preferEntry(a, b dexEntry) bool {
+	if a.Caught != b.Caught {
+		return a.Caught
+	}
+	if len(a.Name) != len(b.Name) {
+		return len(a.Name) < len(b.Name)
+	}
+	return a.Name < b.Name
+}
+
+// speciesIDs returns the national dex numbers in a list of species resources.
+func speciesIDs(species []api.NamedResource) map[int]bool {
+	ids := make(map[int]bool, len(species))
+	for _, s := range species {
+		ids[api.ResourceID(s.URL)] = true
+	}
+	return ids
+}
+
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) * 100 / float64(total)
+}
+
+type completion struct {
+	Name   string `json:"name"`
+	Seen   int    `json:"seen"`
+	Caught int    `json:"caught"`
+	Size   int    `json:"size"`
+}
+
+func completionFor(name string, ids map[int]bool, entries []dexEntry) completion {
+	c := completion{Name: name, Size: len(ids)}
+	for _, entry := range entries {
+		if !ids[entry.ID] {
+			continue
+		}
+		c.Seen++
+		if entry.Caught {
+			c.Caught++
+		}
+	}
+	return c
+}
+
+func printCompletion(w io.Writer, c completion) {
+	fmt.Fprintf(w, "  %-16v caught %v/%v (%.1f%%)  seen %v/%v (%.1f%%)\n",
+		c.Name, c.Caught, c.Size, percent(c.Caught, c.Size), c.Seen, c.Size, percent(c.Seen, c.Size))
+}
+
+type dexStats struct {
+	Generations []completion `json:"generations"`
+	Regions     []completion `json:"regions"`
+}
+
+func (s dexStats) Text(w io.Writer) {
+	fmt.Fprintln(w, "Completion by generation:")
+	for _, c := range s.Generations {
+		printCompletion(w, c)
+	}
+	fmt.Fprintln(w, "Completion by region:")
+	for _, c := range s.Regions {
+		printCompletion(w, c)
+	}
+}
+
+func (s dexStats) Table() ([]string, [][]string) {
+	var rows [][]string
+	add := func(kind string, list []completion) {
+		for _, c := range list {
+			rows = append(rows, []string{kind, c.Name, fmt.Sprint(c.Caught), fmt.Sprint(c.Seen), fmt.Sprint(c.Size)})
+		}
+	}
+	add("generation", s.Generations)
+	add("region", s.Regions)
+	return []string{"scope", "name", "caught", "seen", "size"}, rows
+}
+
+func completionStats(ctx context.Context, config *cli.Config, entries []dexEntry) (dexStats, error) {
+	stats := dexStats{Generations: []completion{}, Regions: []completion{}}
+	generations, err := config.Client.List(ctx, "generation")
+	if err != nil {
+		return stats, err
+	}
+	details, err := fetchGenerations(ctx, config, generations)
+	if err != nil {
+		return stats, err
+	}
+	// Each generation's species count towards the region it introduced.
+	regionIDs := map[string]map[int]bool{}
+	var regions []string
+	for _, generation := range details {
+		ids := speciesIDs(generation.PokemonSpecies)
+		stats.Generations = append(stats.Generations, completionFor(generation.Name, ids, entries))
+
+		region := generation.MainRegion.Name
+		if regionIDs[region] == nil {
+			regions = append(regions, region)
+			regionIDs[region] = map[int]bool{}
+		}
+		for id := range ids {
+			regionIDs[region][id] = true
+		}
+	}
+	for _, region := range regions {
+		stats.Regions = append(stats.Regions, completionFor(region, regionIDs[region], entries))
+	}
+	return stats, nil
+}
+
+// fetchGenerations fetches the generations concurrently, returned in list order.
+func fetchGenerations(ctx context.Context, config *cli.Config, list []api.NamedResource) ([]api.GetGeneration, error) {
+	details := make([]api.GetGeneration, len(list))
+	errs := make([]error, len(list))
+	var wg sync.WaitGroup
+	for i, g := range list {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			details[i], errs[i] = config.Client.Generation(ctx, g.Name)
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return details, nil
+}
+
+type dexList struct {
+	Entries []dexEntry `json:"entries"`
+	Seen    int        `json:"seen"`
+	Caught  int        `json:"caught"`
+}
+
+func (l dexList) Text(w io.Writer) {
+	fmt.Fprintln(w, "Your Pokedex:")
+	for _, entry := range l.Entries {
+		status := "seen"
+		if entry.Caught {
+			status = "caught"
+		}
+		fmt.Fprintf(w, "  #%03d %-16v %v\n", entry.ID, entry.Name, status)
+	}
+	fmt.Fprintf(w, "Seen: %v  Caught: %v\n", l.Seen, l.Caught)
+}
+
+func (l dexList) Table() ([]string, [][]string) {
+	rows := make([][]string, len(l.Entries))
+	for i, entry := range l.Entries {
+		rows[i] = []string{fmt.Sprint(entry.ID), entry.Name, fmt.Sprint(entry.Seen), fmt.Sprint(entry.Caught)}
+	}
+	return []string{"id", "name", "seen", "caught"}, rows
+}
+
+type missingList []dexEntry
+
+func (l missingList) Text(w io.Writer) {
+	fmt.Fprintf(w, "Missing (%v):\n", len(l))
+	for _, entry := range l {
+		fmt.Fprintf(w, "  #%03d %v\n", entry.ID, entry.Name)
+	}
+}
+
+func (l missingList) Table() ([]string, [][]string) {
+	rows := make([][]string, len(l))
+	for i, entry := range l {
+		rows[i] = []string{fmt.Sprint(entry.ID), entry.Name}
+	}
+	return []string{"id", "name"}, rows
+}
+
+// allSpecies lists the species of one generation, or the whole national dex.
+func allSpecies(ctx context.Context, config *cli.Config, generation string) ([]api.NamedResource, error) {
+	if generation != "" {
+		g, err := config.Client.Generation(ctx, generation)
+		if err != nil {
+			return nil, err
+		}
+		return g.PokemonSpecies, nil
+	}
+	return config.Client.List(ctx, "pokemon-species")
+}
+
+func CommandPokedex(ctx context.Context, config *cli.Config, args []string, flags map[string]string) error {
+	entries := dexEntries(config)
+	if flags["stats"] != "" {
+		stats, err := completionStats(ctx, config, entries)
+		if err != nil {
+			return err
+		}
+		return config.Out.Render(stats)
+	}
+
+	var inScope map[int]bool
+	var scope []api.NamedResource
+	if flags["generation"] != "" || flags["missing"] != "" {
+		var err error
+		scope, err = allSpecies(ctx, config, flags["generation"])
+		if err != nil {
+			return err
+		}
+		inScope = speciesIDs(scope)
+	}
+
+	if flags["missing"] != "" {
+		caught := map[int]bool{}
+		for _, entry := range entries {
+			if entry.Caught {
+				caught[entry.ID] = true
+			}
+		}
+		missing := missingList{}
+		for _, species := range scope {
+			if id := api.ResourceID(species.URL); !caught[id] {
+				missing = append(missing, dexEntry{ID: id, Name: species.Name})
+			}
+		}
+		sort.Slice(missing, func(i, j int) bool {
+			return missing[i].ID < missing[j].ID
+		})
+		return config.Out.Render(missing)
+	}
+
+	list := dexList{Entries: []dexEntry{}}
+	for _, entry := range entries {
+		if inScope != nil && !inScope[entry.ID] {
+			continue
+		}
+		if flags["seen"] != "" && entry.Caught || flags["caught"] != "" && !entry.Caught {
+			continue
+		}
+		if entry.Caught {
+			list.Caught++
+		}
+		list.Seen++
+		list.Entries = append(list.Entries, entry)
+	}
+	return config.Out.Render(list)
+}
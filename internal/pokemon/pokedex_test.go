@@ -0,0 +1,75 @@
+package pokemon
+
+import (
+	"testing"
+
+	"github.com/almasx/pokedexcli/internal/api"
+	"github.com/almasx/pokedexcli/internal/cli"
+)
+
+func TestDexEntries(t *testing.T) {
+	config := &cli.Config{
+		Pokedex: map[string]api.GetPokemon{"pikachu": {ID: 25, Name: "pikachu"}},
+		Seen:    map[string]int{"pidgey": 16, "pikachu": 25, "bulbasaur": 1},
+	}
+	// Alternate forms count as their species' national dex number.
+	alolan := api.GetPokemon{ID: 10091, Name: "rattata-alola"}
+	alolan.Species.URL = "https://pokeapi.co/api/v2/pokemon-species/19/"
+	config.Pokedex[alolan.Name] = alolan
+
+	entries := dexEntries(config)
+	expected := []dexEntry{
+		{ID: 1, Name: "bulbasaur", Seen: true},
+		{ID: 16, Name: "pidgey", Seen: true},
+		{ID: 19, Name: "rattata-alola", Seen: true, Caught: true},
+		{ID: 25, Name: "pikachu", Seen: true, Caught: true},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %v entries, got %v", len(expected), entries)
+	}
+	for i := range entries {
+		if entries[i] != expected[i] {
+			t.Errorf("entry %v: expected %+v, got %+v", i, expected[i], entries[i])
+		}
+	}
+
+	kanto := speciesIDs([]api.NamedResource{
+		{Name: "bulbasaur", URL: "https://pokeapi.co/api/v2/pokemon-species/1/"},
+		{Name: "pikachu", URL: "https://pokeapi.co/api/v2/pokemon-species/25/"},
+		{Name: "mew", URL: "https://pokeapi.co/api/v2/pokemon-species/151/"},
+		{Name: "rattata", URL: "https://pokeapi.co/api/v2/pokemon-species/19/"},
+		{Name: "chikorita", URL: "https://pokeapi.co/api/v2/pokemon-species/152/"},
+	})
+	c := completionFor("kanto", kanto, entries)
+	if c.Size != 5 || c.Seen != 3 || c.Caught != 2 {
+		t.Errorf("expected 2 caught and 3 seen of 5, got %+v", c)
+	}
+}
+
+func TestDexEntriesMergesForms(t *testing.T) {
+	alolan := api.GetPokemon{ID: 10091, Name: "rattata-alola"}
+	alolan.Species.URL = "https://pokeapi.co/api/v2/pokemon-species/19/"
+	config := &cli.Config{
+		Pokedex: map[string]api.GetPokemon{alolan.Name: alolan},
+		Seen:    map[string]int{"rattata": 19, "rattata-alola": 19, "raticate": 20, "raticate-alola": 20},
+	}
+
+	entries := dexEntries(config)
+	expected := []dexEntry{
+		{ID: 19, Name: "rattata-alola", Seen: true, Caught: true},
+		{ID: 20, Name: "raticate", Seen: true},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("expected one entry per species, got %+v", entries)
+	}
+	for i := range entries {
+		if entries[i] != expected[i] {
+			t.Errorf("entry %v: expected %+v, got %+v", i, expected[i], entries[i])
+		}
+	}
+
+	c := completionFor("kanto", map[int]bool{19: true}, entries)
+	if c.Size != 1 || c.Seen != 1 || c.Caught != 1 {
+		t.Errorf("expected rattata to count once, got %+v", c)
+	}
+}
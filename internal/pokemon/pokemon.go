@@ -1,125 +1,194 @@
 package pokemon
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"io"
-	"math/rand"
-	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/almasx/pokedexcli/internal/api"
 	"github.com/almasx/pokedexcli/internal/cli"
+	"github.com/almasx/pokedexcli/internal/trainer"
 )
 
-
-
-func fetchPokemon(url string, config *cli.Config) (api.GetPokemon, error) {
-	res := api.GetPokemon{}
-
-	if data, ok := config.Cache.Get(url); ok {
-		err := json.Unmarshal(data, &res)
-		if err != nil {
-			return res, err
-		}
-		return res, nil
+// AddCaught registers the species in the pokedex and adds the new pokemon to
+// the party, or the box when the party is full.
+func AddCaught(config *cli.Config, pokemon_data api.GetPokemon, level int) *trainer.Owned {
+	config.Pokedex[pokemon_data.Name] = pokemon_data
+	config.MarkSeen(pokemon_data.Name, pokemon_data.SpeciesID())
+	owned := &trainer.Owned{
+		Level:    level,
+		Location: config.Location,
+		CaughtAt: time.Now(),
+		Pokemon:  pokemon_data,
 	}
-	
-	data, err := http.Get(url)
-	if err != nil {
-		return api.GetPokemon{}, err
+	if !config.Collection.Add(owned) {
+		config.Out.Printf("Your party is full, so %v was sent to the box.\n", pokemon_data.Name)
 	}
-	defer data.Body.Close()
-	body, err := io.ReadAll(data.Body)
-	if err != nil {
-		return api.GetPokemon{}, err
-	}
-	config.Cache.Add(url, body)
-	err = json.Unmarshal(body, &res)
-	if err != nil {
-		return api.GetPokemon{}, err
-	}
-	return res, nil
+	return owned
 }
 
-func catchPokemon(pokemon_data api.GetPokemon) bool {
-	catch_rate := pokemon_data.BaseExperience 
-	random_number := rand.Intn(catch_rate * 2)
-	return random_number <= catch_rate
+// ThrowResult is the outcome of a single ball throw.
+type ThrowResult struct {
+	Pokemon string `json:"pokemon"`
+	Ball    string `json:"ball"`
+	Shakes  int    `json:"shakes"`
+	Caught  bool   `json:"caught"`
+	// ID is the collection ID of the new pokemon when it was caught.
+	ID int `json:"id,omitempty"`
 }
 
-func CommandCatch(config *cli.Config, args []string) error {
-	if len(args) != 1 {
-		fmt.Println("catch requires a pokemon")
-		return fmt.Errorf("catch requires a pokemon")
+func (r ThrowResult) Text(w io.Writer) {
+	for i := 0; i < min(r.Shakes, 3); i++ {
+		fmt.Fprintln(w, "...the ball shook!")
 	}
+	if !r.Caught {
+		fmt.Fprintln(w, r.Pokemon, "escaped!")
+		return
+	}
+	fmt.Fprintln(w, r.Pokemon, "was caught!")
+	fmt.Fprintln(w, "You may now inspect it with the inspect command.")
+}
+
+func CommandCatch(ctx context.Context, config *cli.Config, args []string, flags map[string]string) error {
 	pokemon := args[0]
 	if pokemon == "" {
-		fmt.Println("pokemon is required")
 		return fmt.Errorf("pokemon is required")
 	}
+	ball, err := LookupBall(flags["ball"])
+	if err != nil {
+		return err
+	}
 
-	if _, ok := config.Pokedex[pokemon]; ok {
-		fmt.Println("pokemon already in pokedex")
-		return fmt.Errorf("pokemon already in pokedex")
+	status, err := ParseStatus(flags["status"])
+	if err != nil {
+		return err
+	}
+	hp := 100
+	if flags["hp"] != "" {
+		hp, err = strconv.Atoi(strings.TrimSuffix(flags["hp"], "%"))
+		if err != nil || hp < 1 || hp > 100 {
+			return fmt.Errorf("--hp must be a percentage from 1 to 100")
+		}
 	}
 
-	fmt.Printf("Throwing a Pokeball at %v...\n", pokemon)	
-	url := fmt.Sprintf("https://pokeapi.co/api/v2/pokemon/%s", pokemon)
-	pokemon_data, err := fetchPokemon(url, config)
+	pokemon_data, err := config.Client.Pokemon(ctx, pokemon)
+	if err != nil {
+		return err
+	}
 
+	wild := NewWild(pokemon_data, DefaultWildLevel)
+	wild.HP = max(wild.MaxHP*hp/100, 1)
+	wild.Status = status
+	result, err := Throw(ctx, config, pokemon_data, wild, ball)
 	if err != nil {
 		return err
 	}
+	return config.Out.Render(result)
+}
+
+// Throw throws ball at a wild pokemon in the state wild and adds it to the
+// collection if it is caught. The caller renders the result.
+func Throw(ctx context.Context, config *cli.Config, pokemon_data api.GetPokemon, wild Wild, ball Ball) (ThrowResult, error) {
+	species, err := config.Client.PokemonSpecies(ctx, pokemon_data.Species.Name)
+	if err != nil {
+		return ThrowResult{}, err
+	}
 
-	caught := catchPokemon(pokemon_data)
-	if caught {
-		fmt.Println( pokemon, "was caught!")
-		fmt.Println("You may now inspect it with the inspect command.")
-		config.Pokedex[pokemon] = pokemon_data
-	} else {
-		fmt.Println( pokemon, "escaped!")
+	config.Out.Printf("Throwing a %v at %v...\n", ball.Name, pokemon_data.Name)
+	capture := Capture(species.CaptureRate, wild, ball, config.Rand.Intn)
+	result := ThrowResult{
+		Pokemon: pokemon_data.Name,
+		Ball:    ball.Name,
+		Shakes:  capture.Shakes,
+		Caught:  capture.Caught,
+	}
+	if result.Caught {
+		result.ID = AddCaught(config, pokemon_data, wild.Level).ID
 	}
+	return result, nil
+}
 
-	return nil
+type statValue struct {
+	Name string `json:"name"`
+	Base int    `json:"base"`
 }
 
-func CommandInspect(config *cli.Config, args []string) error {
-	if len(args) != 1 {
-		fmt.Println("inspect requires a pokemon")
-		return fmt.Errorf("inspect requires a pokemon")
+type inspectResult struct {
+	ID       int         `json:"id,omitempty"`
+	Nickname string      `json:"nickname,omitempty"`
+	Level    int         `json:"level,omitempty"`
+	Location string      `json:"location,omitempty"`
+	CaughtAt *time.Time  `json:"caught_at,omitempty"`
+	Name     string      `json:"name"`
+	Height   int         `json:"height"`
+	Weight   int         `json:"weight"`
+	Stats    []statValue `json:"stats"`
+	Types    []string    `json:"types"`
+}
+
+func (r inspectResult) Text(w io.Writer) {
+	if r.ID != 0 {
+		fmt.Fprintf(w, "ID: #%v\n", r.ID)
+		if r.Nickname != "" {
+			fmt.Fprintf(w, "Nickname: %v\n", r.Nickname)
+		}
+		fmt.Fprintf(w, "Level: %v\n", r.Level)
+		if r.Location != "" {
+			fmt.Fprintf(w, "Caught at: %v\n", r.Location)
+		}
+		fmt.Fprintf(w, "Caught on: %v\n", r.CaughtAt.Format(time.DateTime))
 	}
+	fmt.Fprintf(w, "Name: %v\n", r.Name)
+	fmt.Fprintf(w, "Height: %v\n", r.Height)
+	fmt.Fprintf(w, "Weight: %v\n", r.Weight)
+
+	fmt.Fprintf(w, "Stats: \n")
+	for _, stat := range r.Stats {
+		fmt.Fprintf(w, "  -%v: %v\n", stat.Name, stat.Base)
+	}
+
+	fmt.Fprintf(w, "Types: \n")
+	for _, type_ := range r.Types {
+		fmt.Fprintf(w, "  - %v\n", type_)
+	}
+}
+
+func CommandInspect(ctx context.Context, config *cli.Config, args []string, flags map[string]string) error {
 	pokemon := args[0]
 	if pokemon == "" {
-		fmt.Println("pokemon is required")
 		return fmt.Errorf("pokemon is required")
 	}
 
-	if _, ok := config.Pokedex[pokemon]; !ok {
-		fmt.Println("you have not caught that pokemon")
+	owned, err := config.Collection.Find(pokemon)
+	pokemon_data, ok := config.Pokedex[pokemon]
+	if err == nil {
+		pokemon_data = owned.Pokemon
+	} else if !ok {
 		return fmt.Errorf("you have not caught that pokemon")
 	}
 
-	fmt.Printf("Name: %v\n", config.Pokedex[pokemon].Name)
-	fmt.Printf("Height: %v\n", config.Pokedex[pokemon].Height)
-	fmt.Printf("Weight: %v\n", config.Pokedex[pokemon].Weight)
-
-	fmt.Printf("Stats: \n")
-	for _, stat := range config.Pokedex[pokemon].Stats {
-		fmt.Printf("  -%v: %v\n", stat.Stat.Name, stat.BaseStat)
+	result := inspectResult{
+		Name:   pokemon_data.Name,
+		Height: pokemon_data.Height,
+		Weight: pokemon_data.Weight,
+		Stats:  []statValue{},
+		Types:  []string{},
 	}
-
-	fmt.Printf("Types: \n")
-	for _, type_ := range config.Pokedex[pokemon].Types {
-		fmt.Printf("  - %v\n", type_.Type.Name)
+	if owned != nil {
+		result.ID = owned.ID
+		result.Nickname = owned.Nickname
+		result.Level = owned.Level
+		result.Location = owned.Location
+		result.CaughtAt = &owned.CaughtAt
 	}
-	
-	return nil
-}
-
-func CommandPokedex(config *cli.Config, args []string) error {	
-	fmt.Println("Your Pokedex:")
-	for _, pokemon := range config.Pokedex {
-		fmt.Printf("  - %v\n", pokemon.Name)
+	for _, stat := range pokemon_data.Stats {
+		result.Stats = append(result.Stats, statValue{Name: stat.Stat.Name, Base: stat.BaseStat})
+	}
+	for _, type_ := range pokemon_data.Types {
+		result.Types = append(result.Types, type_.Type.Name)
 	}
-	return nil
+	return config.Out.Render(result)
 }
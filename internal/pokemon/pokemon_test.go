@@ -0,0 +1,46 @@
+package pokemon
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/almasx/pokedexcli/internal/api"
+	"github.com/almasx/pokedexcli/internal/apitest"
+	"github.com/almasx/pokedexcli/internal/cli"
+	"github.com/almasx/pokedexcli/internal/output"
+	"github.com/almasx/pokedexcli/internal/trainer"
+)
+
+func TestCatchWeakened(t *testing.T) {
+	config := &cli.Config{
+		Client: apitest.NewClient(t, apitest.Serve(map[string]string{
+			"/pokemon/caterpie":         `{"id": 10, "name": "caterpie", "species": {"name": "caterpie"}}`,
+			"/pokemon-species/caterpie": `{"id": 10, "name": "caterpie", "capture_rate": 255}`,
+		})),
+		Pokedex:    map[string]api.GetPokemon{},
+		Collection: trainer.NewCollection(),
+		Out:        &output.Renderer{Format: output.Text, Out: io.Discard, Err: io.Discard},
+	}
+	config.SetSeed(1)
+
+	ctx := context.Background()
+	for _, flags := range []map[string]string{
+		{"hp": "0"},
+		{"hp": "full"},
+		{"status": "confused"},
+	} {
+		if err := CommandCatch(ctx, config, []string{"caterpie"}, flags); err == nil {
+			t.Errorf("expected %v to be rejected", flags)
+		}
+	}
+
+	// Asleep at 1% HP, even a Poke Ball cannot miss a capture rate of 255.
+	flags := map[string]string{"hp": "1%", "status": "sleep"}
+	if err := CommandCatch(ctx, config, []string{"caterpie"}, flags); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := config.Pokedex["caterpie"]; !ok {
+		t.Errorf("expected a weakened caterpie to be caught")
+	}
+}
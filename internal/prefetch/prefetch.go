@@ -0,0 +1,243 @@
+package prefetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+
+	"github.com/almasx/pokedexcli/internal/api"
+	"github.com/almasx/pokedexcli/internal/cli"
+	"github.com/almasx/pokedexcli/internal/pokecache"
+)
+
+const DefaultWorkers = 4
+
+// resource is one API resource to fetch, e.g. {"location-area", "pallet-town-area"}.
+type resource struct {
+	kind string
+	name string
+}
+
+func (r resource) String() string {
+	return r.kind + "/" + r.name
+}
+
+// visit fetches r and returns the resources it leads to: a region's
+// locations, a location's areas, an area's wild pokemon, a generation's
+// species, and each pokemon's species and each species' default pokemon.
+func visit(ctx context.Context, client *api.Client, r resource) ([]resource, error) {
+	var next []resource
+	switch r.kind {
+	case "region":
+		region, err := client.Region(ctx, r.name)
+		if err != nil {
+			return nil, err
+		}
+		for _, location := range region.Locations {
+			next = append(next, resource{"location", location.Name})
+		}
+	case "location":
+		location, err := client.Location(ctx, r.name)
+		if err != nil {
+			return nil, err
+		}
+		for _, area := range location.Areas {
+			next = append(next, resource{"location-area", area.Name})
+		}
+	case "location-area":
+		area, err := client.LocationArea(ctx, r.name)
+		if err != nil {
+			return nil, err
+		}
+		for _, encounter := range area.PokemonEncounters {
+			next = append(next, resource{"pokemon", encounter.Pokemon.Name})
+		}
+	case "generation":
+		generation, err := client.Generation(ctx, r.name)
+		if err != nil {
+			return nil, err
+		}
+		for _, species := range generation.PokemonSpecies {
+			next = append(next, resource{"pokemon-species", species.Name})
+		}
+	case "pokemon":
+		pokemon, err := client.Pokemon(ctx, r.name)
+		if err != nil {
+			return nil, err
+		}
+		next = append(next, resource{"pokemon-species", pokemon.Species.Name})
+	case "pokemon-species":
+		species, err := client.PokemonSpecies(ctx, r.name)
+		if err != nil {
+			return nil, err
+		}
+		for _, variety := range species.Varieties {
+			if variety.IsDefault {
+				next = append(next, resource{"pokemon", variety.Pokemon.Name})
+			}
+		}
+	default:
+		return nil, fmt.Errorf("cannot prefetch %v", r.kind)
+	}
+	return next, nil
+}
+
+// Progress is told about work as the crawl finds and finishes it.
+type Progress interface {
+	Grow(n int)
+	Step()
+}
+
+// Report is the outcome of a crawl.
+type Report struct {
+	Fetched int `json:"fetched"`
+	// Cached resources were already fresh in the cache, e.g. from an
+	// earlier, interrupted prefetch.
+	Cached int      `json:"cached"`
+	Failed []string `json:"failed"`
+	// Remaining resources were found but not fetched before ctx ended.
+	Remaining int `json:"remaining"`
+}
+
+// Done is how many resources the crawl got through, successfully or not.
+func (r Report) Done() int {
+	return r.Fetched + r.Cached + len(r.Failed)
+}
+
+type visited struct {
+	resource resource
+	next     []resource
+	cached   bool
+	err      error
+}
+
+// Crawl fetches root and everything it leads to with up to workers requests
+// in flight. Requests still go through the client, so its rate limit and
+// request coalescing apply. A failure below the root is recorded in the
+// report and the crawl carries on; if ctx ends, Crawl stops handing out work,
+// waits for fetches in flight and returns the report with ctx's error.
+func Crawl(ctx context.Context, client *api.Client, root resource, workers int, progress Progress) (Report, error) {
+	workers = max(workers, 1)
+	report := Report{Failed: []string{}}
+	tasks := make(chan resource)
+	results := make(chan visited)
+	for i := 0; i < workers; i++ {
+		go func() {
+			for r := range tasks {
+				cached := isCached(client, r)
+				next, err := visit(ctx, client, r)
+				results <- visited{resource: r, next: next, cached: cached, err: err}
+			}
+		}()
+	}
+	defer close(tasks)
+
+	seen := map[resource]bool{root: true}
+	queue := []resource{root}
+	progress.Grow(1)
+	inFlight := 0
+	for inFlight > 0 || (len(queue) > 0 && ctx.Err() == nil) {
+		var send chan resource
+		var head resource
+		if len(queue) > 0 && ctx.Err() == nil {
+			send, head = tasks, queue[0]
+		}
+
+		select {
+		case send <- head:
+			queue = queue[1:]
+			inFlight++
+		case v := <-results:
+			inFlight--
+			switch {
+			case ctx.Err() != nil && v.err != nil:
+				// Interrupted mid-fetch; it is fetched again on resume.
+				queue = append(queue, v.resource)
+				continue
+			case v.err != nil && v.resource == root:
+				return report, v.err
+			case v.err != nil:
+				report.Failed = append(report.Failed, fmt.Sprintf("%v: %v", v.resource, v.err))
+			case v.cached:
+				report.Cached++
+			default:
+				report.Fetched++
+			}
+			progress.Step()
+			found := 0
+			for _, r := range v.next {
+				if !seen[r] {
+					seen[r] = true
+					queue = append(queue, r)
+					found++
+				}
+			}
+			progress.Grow(found)
+		}
+	}
+	report.Remaining = len(queue)
+	return report, ctx.Err()
+}
+
+func isCached(client *api.Client, r resource) bool {
+	if client.Cache == nil {
+		return false
+	}
+	return client.Cache.Peek(client.URL(r.kind+"/"+url.PathEscape(r.name))) == pokecache.Fresh
+}
+
+type prefetchResult struct {
+	Target string `json:"target"`
+	Report
+}
+
+func (p prefetchResult) Text(w io.Writer) {
+	fmt.Fprintf(w, "Prefetched %v: %v fetched, %v already cached\n", p.Target, p.Fetched, p.Cached)
+	if len(p.Failed) > 0 {
+		fmt.Fprintf(w, "%v failed:\n", len(p.Failed))
+		for _, failure := range p.Failed {
+			fmt.Fprintln(w, " - ", failure)
+		}
+	}
+}
+
+// CommandPrefetch warms the cache with everything under a region, a
+// generation or a location area.
+func CommandPrefetch(ctx context.Context, config *cli.Config, args []string, flags map[string]string) error {
+	workers := DefaultWorkers
+	if flags["workers"] != "" {
+		var err error
+		workers, err = strconv.Atoi(flags["workers"])
+		if err != nil || workers < 1 {
+			return fmt.Errorf("--workers must be a positive number")
+		}
+	}
+
+	var root resource
+	switch args[0] {
+	case "region", "generation":
+		root = resource{args[0], args[1]}
+	case "area":
+		root = resource{"location-area", args[1]}
+	default:
+		return fmt.Errorf("can only prefetch a region, generation or area, not %q", args[0])
+	}
+
+	progress := config.Out.Progress("Prefetching " + root.String())
+	report, err := Crawl(ctx, config.Client, root, workers, progress)
+	progress.Finish()
+	if errors.Is(err, api.ErrNotFound) {
+		return fmt.Errorf("unknown %v: %v", args[0], args[1])
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			config.Out.Printf("Stopped after %v of %v resources; run the same prefetch again to resume.\n",
+				report.Done(), report.Done()+report.Remaining)
+		}
+		return err
+	}
+	return config.Out.Render(prefetchResult{Target: root.String(), Report: report})
+}
@@ -0,0 +1,112 @@
+package prefetch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/almasx/pokedexcli/internal/api"
+	"github.com/almasx/pokedexcli/internal/apitest"
+)
+
+// kanto is a tiny region: two locations sharing pidgey, and a missing pokemon.
+var kanto = map[string]string{
+	"/region/kanto":               `{"name": "kanto", "locations": [{"name": "route-1"}, {"name": "route-2"}]}`,
+	"/location/route-1":           `{"name": "route-1", "areas": [{"name": "route-1-area"}]}`,
+	"/location/route-2":           `{"name": "route-2", "areas": [{"name": "route-2-area"}]}`,
+	"/location-area/route-1-area": `{"name": "route-1-area", "pokemon_encounters": [{"pokemon": {"name": "pidgey"}}, {"pokemon": {"name": "rattata"}}]}`,
+	"/location-area/route-2-area": `{"name": "route-2-area", "pokemon_encounters": [{"pokemon": {"name": "pidgey"}}, {"pokemon": {"name": "missingno"}}]}`,
+	"/pokemon/pidgey":             `{"name": "pidgey", "species": {"name": "pidgey"}}`,
+	"/pokemon/rattata":            `{"name": "rattata", "species": {"name": "rattata"}}`,
+	"/pokemon-species/pidgey":     `{"name": "pidgey", "varieties": [{"is_default": true, "pokemon": {"name": "pidgey"}}]}`,
+	"/pokemon-species/rattata":    `{"name": "rattata", "varieties": [{"is_default": true, "pokemon": {"name": "rattata"}}]}`,
+}
+
+type counter struct {
+	grown, steps int
+}
+
+func (c *counter) Grow(n int) { c.grown += n }
+func (c *counter) Step()      { c.steps++ }
+
+func TestCrawl(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string]int{}
+	serve := apitest.Serve(kanto)
+	client := apitest.NewClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+		serve(w, r)
+	})
+
+	progress := &counter{}
+	report, err := Crawl(context.Background(), client, resource{"region", "kanto"}, 3, progress)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Fetched != len(kanto) || len(report.Failed) != 1 || !strings.HasPrefix(report.Failed[0], "pokemon/missingno") {
+		t.Errorf("expected %v fetched and missingno failed, got %+v", len(kanto), report)
+	}
+	for path, n := range requests {
+		if n != 1 {
+			t.Errorf("expected %v to be requested once, got %v", path, n)
+		}
+	}
+	if progress.grown != progress.steps || progress.steps != report.Done() {
+		t.Errorf("expected progress to finish at %v, got %+v", report.Done(), progress)
+	}
+
+	report, err = Crawl(context.Background(), client, resource{"region", "kanto"}, 3, &counter{})
+	if err != nil || report.Cached != len(kanto) || report.Fetched != 0 {
+		t.Errorf("expected a second crawl to find everything cached, got %+v, %v", report, err)
+	}
+}
+
+func TestCrawlUnknownRoot(t *testing.T) {
+	client := apitest.NewClient(t, http.NotFound)
+	if _, err := Crawl(context.Background(), client, resource{"region", "johto"}, 2, &counter{}); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
+
+func TestCrawlInterrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var interrupt atomic.Bool
+	interrupt.Store(true)
+	release := make(chan struct{})
+	serve := apitest.Serve(kanto)
+	client := apitest.NewClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if interrupt.Load() && strings.HasPrefix(r.URL.Path, "/location-area/") {
+			cancel()
+			<-release
+		}
+		serve(w, r)
+	})
+
+	// One worker makes the order predictable: the region, both locations,
+	// then the first area, which interrupts the crawl.
+	report, err := Crawl(ctx, client, resource{"region", "kanto"}, 1, &counter{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the crawl to stop when cancelled, got %v", err)
+	}
+	if report.Fetched != 3 || report.Remaining != 2 || len(report.Failed) != 0 {
+		t.Errorf("expected 3 fetched and both areas remaining, got %+v", report)
+	}
+
+	// The interrupted request is not cancelled with the crawl; it finishes
+	// in the background and its area is cached for the next crawl.
+	interrupt.Store(false)
+	close(release)
+	if _, err := client.Fetch(context.Background(), client.URL("location-area/route-1-area")); err != nil {
+		t.Fatal(err)
+	}
+	report, err = Crawl(context.Background(), client, resource{"region", "kanto"}, 1, &counter{})
+	if err != nil || report.Cached != 4 || report.Fetched != len(kanto)-4 {
+		t.Errorf("expected a resumed crawl to skip the 4 cached resources, got %+v, %v", report, err)
+	}
+}
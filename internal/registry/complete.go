@@ -0,0 +1,72 @@
+package registry
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/almasx/pokedexcli/internal/cli"
+)
+
+// Complete returns the candidates for the word being typed at the end of
+// line: a command name for the first word, a flag after "-", and otherwise
+// whatever the positional argument at that index offers.
+func (r *Registry) Complete(config *cli.Config, line string) []string {
+	words := strings.Fields(line)
+	prefix := ""
+	if len(words) > 0 && !strings.HasSuffix(line, " ") {
+		prefix = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+
+	if len(words) == 0 {
+		return matching(r.Names(), prefix)
+	}
+	c, ok := r.Lookup(words[0])
+	if !ok {
+		return nil
+	}
+
+	if strings.HasPrefix(prefix, "-") {
+		var flags []string
+		for _, flag := range c.Flags {
+			flags = append(flags, "--"+flag.Name)
+		}
+		return matching(flags, prefix)
+	}
+
+	// Count the positional arguments before the current word, skipping flags
+	// and the values of flags that take one.
+	index := 0
+	args := words[1:]
+	for i := 0; i < len(args); i++ {
+		if !strings.HasPrefix(args[i], "-") {
+			index++
+			continue
+		}
+		if flag, ok := c.flag(strings.TrimLeft(args[i], "-")); ok && flag.Value != "" {
+			if i == len(args)-1 {
+				// The current word is this flag's value.
+				return nil
+			}
+			i++
+		}
+	}
+	if index >= len(c.Args) || c.Args[index].Complete == nil {
+		return nil
+	}
+	return matching(c.Args[index].Complete(config), prefix)
+}
+
+// matching returns the sorted, de-duplicated candidates that start with prefix.
+func matching(candidates []string, prefix string) []string {
+	seen := map[string]bool{}
+	var matches []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, prefix) && !seen[candidate] {
+			seen[candidate] = true
+			matches = append(matches, candidate)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
@@ -0,0 +1,106 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/almasx/pokedexcli/internal/cli"
+)
+
+type helpEntry struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases,omitempty"`
+	Usage   string   `json:"usage"`
+	Summary string   `json:"summary"`
+}
+
+type helpList []helpEntry
+
+func (l helpList) Text(w io.Writer) {
+	fmt.Fprintln(w, "Welcome to the Pokedex!")
+	fmt.Fprintln(w, "Usage:")
+	fmt.Fprintln(w, "")
+	for _, entry := range l {
+		fmt.Fprintf(w, "%v - %v\n", entry.Usage, entry.Summary)
+	}
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Run help <command> for details, or pass -o <format> to any command.")
+}
+
+func (l helpList) Table() ([]string, [][]string) {
+	rows := make([][]string, len(l))
+	for i, entry := range l {
+		rows[i] = []string{entry.Name, strings.Join(entry.Aliases, " "), entry.Usage, entry.Summary}
+	}
+	return []string{"name", "aliases", "usage", "summary"}, rows
+}
+
+type flagHelp struct {
+	Name  string `json:"name"`
+	Value string `json:"value,omitempty"`
+	Help  string `json:"help"`
+}
+
+type commandHelp struct {
+	helpEntry
+	Help  string     `json:"help,omitempty"`
+	Flags []flagHelp `json:"flags,omitempty"`
+}
+
+func (h commandHelp) Text(w io.Writer) {
+	fmt.Fprintf(w, "usage: %v\n", h.Usage)
+	if len(h.Aliases) > 0 {
+		fmt.Fprintf(w, "aliases: %v\n", strings.Join(h.Aliases, ", "))
+	}
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, h.Summary)
+	if h.Help != "" {
+		fmt.Fprintln(w, h.Help)
+	}
+	if len(h.Flags) > 0 {
+		fmt.Fprintln(w, "")
+		fmt.Fprintln(w, "Flags:")
+		for _, flag := range h.Flags {
+			name := "--" + flag.Name
+			if flag.Value != "" {
+				name += " <" + flag.Value + ">"
+			}
+			fmt.Fprintf(w, "  %-24v %v\n", name, flag.Help)
+		}
+	}
+}
+
+func entryFor(c *Command) helpEntry {
+	return helpEntry{Name: c.Name, Aliases: c.Aliases, Usage: c.Usage(), Summary: c.Summary}
+}
+
+// HelpCommand builds the help command for r.
+func (r *Registry) HelpCommand() *Command {
+	return &Command{
+		Name: "help",
+		Args: []Arg{{Name: "command", Optional: true, Complete: func(*cli.Config) []string {
+			return r.Names()
+		}}},
+		Summary: "Show all commands, or details for one",
+		Callback: func(ctx context.Context, config *cli.Config, args []string, flags map[string]string) error {
+			if len(args) == 0 {
+				list := make(helpList, 0, len(r.commands))
+				for _, c := range r.commands {
+					list = append(list, entryFor(c))
+				}
+				return config.Out.Render(list)
+			}
+			c, ok := r.Lookup(args[0])
+			if !ok {
+				return r.UnknownError(args[0])
+			}
+			help := commandHelp{helpEntry: entryFor(c), Help: c.Help}
+			for _, flag := range c.Flags {
+				help.Flags = append(help.Flags, flagHelp(flag))
+			}
+			return config.Out.Render(help)
+		},
+	}
+}
@@ -0,0 +1,224 @@
+// Package registry describes every REPL command in one place: its name,
+// aliases, arguments, flags and help. Help text, usage errors and "did you
+// mean" suggestions are all generated from these declarations.
+package registry
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/almasx/pokedexcli/internal/cli"
+)
+
+// Callback runs a command with its positional args and the flags it was
+// given, as parsed by Parse. ctx is cancelled when the user interrupts it.
+// Boolean flags are "true" when present.
+type Callback func(ctx context.Context, config *cli.Config, args []string, flags map[string]string) error
+
+// Arg is one positional argument. Complete, if set, lists the values tab
+// completion offers for it.
+type Arg struct {
+	Name     string
+	Optional bool
+	Complete func(*cli.Config) []string
+}
+
+// Flag is a --name option. Flags with a Value placeholder take a value,
+// the rest are booleans.
+type Flag struct {
+	Name  string
+	Value string
+	Help  string
+}
+
+type Command struct {
+	Name    string
+	Aliases []string
+	Args    []Arg
+	Flags   []Flag
+	// Summary is the one line shown by help; Help is the longer text shown
+	// by help <command>.
+	Summary  string
+	Help     string
+	Callback Callback
+}
+
+// Usage renders the command line, e.g. "catch <pokemon> [--ball <ball>]".
+func (c *Command) Usage() string {
+	parts := []string{c.Name}
+	for _, arg := range c.Args {
+		if arg.Optional {
+			parts = append(parts, "["+arg.Name+"]")
+		} else {
+			parts = append(parts, "<"+arg.Name+">")
+		}
+	}
+	for _, flag := range c.Flags {
+		if flag.Value != "" {
+			parts = append(parts, fmt.Sprintf("[--%v <%v>]", flag.Name, flag.Value))
+		} else {
+			parts = append(parts, fmt.Sprintf("[--%v]", flag.Name))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func (c *Command) valueFlags() []string {
+	var names []string
+	for _, flag := range c.Flags {
+		if flag.Value != "" {
+			names = append(names, flag.Name)
+		}
+	}
+	return names
+}
+
+func (c *Command) flag(name string) (Flag, bool) {
+	for _, flag := range c.Flags {
+		if flag.Name == name {
+			return flag, true
+		}
+	}
+	return Flag{}, false
+}
+
+// Parse splits args into positional arguments and flags, and checks them
+// against the declarations.
+func (c *Command) Parse(args []string) ([]string, map[string]string, error) {
+	positional, flags, err := cli.SplitFlags(args, c.valueFlags()...)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := c.validate(positional, flags); err != nil {
+		return nil, nil, err
+	}
+	return positional, flags, nil
+}
+
+func (c *Command) validate(positional []string, flags map[string]string) error {
+	for name, value := range flags {
+		flag, ok := c.flag(name)
+		if !ok {
+			return fmt.Errorf("%v does not take --%v\nusage: %v", c.Name, name, c.Usage())
+		}
+		if flag.Value == "" && value != "true" {
+			return fmt.Errorf("--%v does not take a value\nusage: %v", name, c.Usage())
+		}
+	}
+
+	required := 0
+	for _, arg := range c.Args {
+		if !arg.Optional {
+			required++
+		}
+	}
+	switch {
+	case len(positional) < required:
+		return fmt.Errorf("%v requires %v\nusage: %v", c.Name, c.Args[len(positional)].Name, c.Usage())
+	case len(positional) > len(c.Args):
+		return fmt.Errorf("too many arguments to %v\nusage: %v", c.Name, c.Usage())
+	}
+	return nil
+}
+
+// Registry holds commands in the order they were registered.
+type Registry struct {
+	commands []*Command
+	byName   map[string]*Command
+}
+
+func New() *Registry {
+	return &Registry{byName: make(map[string]*Command)}
+}
+
+// Register adds c under its name and aliases. Registering a name twice is a
+// programming error and panics.
+func (r *Registry) Register(c *Command) {
+	for _, name := range append([]string{c.Name}, c.Aliases...) {
+		if _, exists := r.byName[name]; exists {
+			panic(fmt.Sprintf("registry: %q registered twice", name))
+		}
+		r.byName[name] = c
+	}
+	r.commands = append(r.commands, c)
+}
+
+// Lookup finds a command by name or alias.
+func (r *Registry) Lookup(name string) (*Command, bool) {
+	c, ok := r.byName[strings.ToLower(name)]
+	return c, ok
+}
+
+func (r *Registry) Commands() []*Command {
+	return r.commands
+}
+
+// Names lists every name and alias in sorted order.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.byName))
+	for name := range r.byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// maxSuggestDistance is how many edits a typo may be from a command to be
+// suggested.
+const maxSuggestDistance = 2
+
+// Suggest returns the commands closest to an unknown name, nearest first.
+func (r *Registry) Suggest(name string) []string {
+	name = strings.ToLower(name)
+	type candidate struct {
+		name     string
+		distance int
+	}
+	var candidates []candidate
+	for _, known := range r.Names() {
+		d := distance(name, known)
+		if d <= maxSuggestDistance || strings.HasPrefix(known, name) && len(name) > 1 {
+			candidates = append(candidates, candidate{known, d})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].distance < candidates[j].distance
+	})
+	suggestions := make([]string, len(candidates))
+	for i, c := range candidates {
+		suggestions[i] = c.name
+	}
+	return suggestions
+}
+
+// UnknownError describes a command that is not registered, with suggestions.
+func (r *Registry) UnknownError(name string) error {
+	suggestions := r.Suggest(name)
+	if len(suggestions) == 0 {
+		return fmt.Errorf("unknown command %q, try help", name)
+	}
+	return fmt.Errorf("unknown command %q, did you mean %v?", name, strings.Join(suggestions, " or "))
+}
+
+// distance is the Levenshtein edit distance between a and b.
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
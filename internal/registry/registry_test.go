@@ -0,0 +1,131 @@
+package registry
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/almasx/pokedexcli/internal/cli"
+)
+
+func testRegistry() *Registry {
+	r := New()
+	r.Register(&Command{Name: "catch", Args: []Arg{{Name: "pokemon"}}, Flags: []Flag{{Name: "ball", Value: "ball"}}})
+	r.Register(&Command{Name: "walk", Aliases: []string{"encounter"}})
+	r.Register(&Command{Name: "map"})
+	r.Register(&Command{Name: "mapb"})
+	r.Register(&Command{Name: "save", Args: []Arg{{Name: "slot", Optional: true}}})
+	r.Register(&Command{Name: "pokedex", Flags: []Flag{{Name: "seen"}}})
+	return r
+}
+
+func TestUsage(t *testing.T) {
+	r := testRegistry()
+	cases := map[string]string{
+		"catch":   "catch <pokemon> [--ball <ball>]",
+		"save":    "save [slot]",
+		"pokedex": "pokedex [--seen]",
+	}
+	for name, expected := range cases {
+		c, _ := r.Lookup(name)
+		if actual := c.Usage(); actual != expected {
+			t.Errorf("%v: expected %q, got %q", name, expected, actual)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	r := testRegistry()
+	cases := []struct {
+		command string
+		args    []string
+		err     string
+	}{
+		{"catch", []string{"pikachu"}, ""},
+		{"catch", []string{"pikachu", "--ball", "ultra"}, ""},
+		{"catch", nil, "catch requires pokemon"},
+		{"catch", []string{"pikachu", "eevee"}, "too many arguments"},
+		{"catch", []string{"pikachu", "--shiny"}, "does not take --shiny"},
+		{"catch", []string{"pikachu", "--ball"}, "requires a value"},
+		{"save", nil, ""},
+		{"save", []string{"a"}, ""},
+		{"pokedex", []string{"--seen=yes"}, "does not take a value"},
+	}
+	for _, c := range cases {
+		command, _ := r.Lookup(c.command)
+		_, _, err := command.Parse(c.args)
+		if c.err == "" && err != nil || c.err != "" && (err == nil || !strings.Contains(err.Error(), c.err)) {
+			t.Errorf("%v %v: expected error %q, got %v", c.command, c.args, c.err, err)
+		}
+	}
+
+	catch, _ := r.Lookup("catch")
+	args, flags, err := catch.Parse([]string{"--ball", "ultra", "pikachu"})
+	if err != nil || !reflect.DeepEqual(args, []string{"pikachu"}) || !reflect.DeepEqual(flags, map[string]string{"ball": "ultra"}) {
+		t.Errorf("expected pikachu with an ultra ball, got %v %v, %v", args, flags, err)
+	}
+}
+
+func TestLookupAlias(t *testing.T) {
+	r := testRegistry()
+	c, ok := r.Lookup("Encounter")
+	if !ok || c.Name != "walk" {
+		t.Errorf("expected encounter to resolve to walk, got %v", c)
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	r := testRegistry()
+	cases := []struct {
+		input    string
+		expected []string
+	}{
+		{"cacth", []string{"catch"}},
+		{"mpa", []string{"map", "mapb"}},
+		{"pokedx", []string{"pokedex"}},
+		{"xyzzy", []string{}},
+	}
+	for _, c := range cases {
+		if actual := r.Suggest(c.input); !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("%v: expected %v, got %v", c.input, c.expected, actual)
+		}
+	}
+}
+
+func TestRegisterDuplicate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected registering walk twice to panic")
+		}
+	}()
+	r := testRegistry()
+	r.Register(&Command{Name: "walk"})
+}
+
+func TestComplete(t *testing.T) {
+	r := testRegistry()
+	catch, _ := r.Lookup("catch")
+	catch.Args[0].Complete = func(config *cli.Config) []string {
+		return []string{"pikachu", "pidgey", "eevee", "pikachu"}
+	}
+	cases := []struct {
+		line     string
+		expected []string
+	}{
+		{"", []string{"catch", "encounter", "map", "mapb", "pokedex", "save", "walk"}},
+		{"ma", []string{"map", "mapb"}},
+		{"catch pi", []string{"pidgey", "pikachu"}},
+		{"catch ", []string{"eevee", "pidgey", "pikachu"}},
+		{"catch --", []string{"--ball"}},
+		{"catch --ball ", nil},
+		{"catch --ball ultra pi", []string{"pidgey", "pikachu"}},
+		{"catch pikachu ", nil},
+		{"save ", nil},
+		{"nope ", nil},
+	}
+	for _, c := range cases {
+		if actual := r.Complete(&cli.Config{}, c.line); !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("%q: expected %v, got %v", c.line, c.expected, actual)
+		}
+	}
+}
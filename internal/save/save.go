@@ -0,0 +1,261 @@
+package savepkg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/almasx/pokedexcli/internal/api"
+	"github.com/almasx/pokedexcli/internal/cli"
+	"github.com/almasx/pokedexcli/internal/trainer"
+)
+
+const (
+	CurrentVersion = 2
+	DefaultSlot    = "default"
+)
+
+type File struct {
+	Version     int                `json:"version"`
+	SavedAt     time.Time          `json:"saved_at"`
+	Seed        int64              `json:"seed"`
+	Next        string             `json:"next"`
+	Prev        string             `json:"prev"`
+	Location    string             `json:"location,omitempty"`
+	GameVersion string             `json:"game_version,omitempty"`
+	Pokedex     []api.GetPokemon   `json:"pokedex"`
+	Seen        map[string]int     `json:"seen,omitempty"`
+	Collection  trainer.Collection `json:"collection"`
+}
+
+// migrations[i] upgrades a save file from version i+1 to version i+2.
+var migrations = []func(map[string]json.RawMessage) error{
+	migrateV1,
+}
+
+// migrateV1 splits the v1 list of caught pokemon, one per species, into the
+// pokedex and a collection of individually owned pokemon.
+func migrateV1(raw map[string]json.RawMessage) error {
+	var caught []struct {
+		CaughtAt time.Time      `json:"caught_at"`
+		Pokemon  api.GetPokemon `json:"pokemon"`
+	}
+	if data, ok := raw["pokemon"]; ok {
+		if err := json.Unmarshal(data, &caught); err != nil {
+			return err
+		}
+	}
+
+	pokedex := []api.GetPokemon{}
+	collection := trainer.NewCollection()
+	for _, entry := range caught {
+		pokedex = append(pokedex, entry.Pokemon)
+		collection.Add(&trainer.Owned{
+			// v1 pokemon were all caught at the fixed wild level.
+			Level:    50,
+			CaughtAt: entry.CaughtAt,
+			Pokemon:  entry.Pokemon,
+		})
+	}
+
+	var err error
+	if raw["pokedex"], err = json.Marshal(pokedex); err != nil {
+		return err
+	}
+	if raw["collection"], err = json.Marshal(collection); err != nil {
+		return err
+	}
+	delete(raw, "pokemon")
+	return nil
+}
+
+func slotPath(dir, slot string) (string, error) {
+	if slot == "" || strings.ContainsAny(slot, `/\.`) {
+		return "", fmt.Errorf("invalid save slot %q", slot)
+	}
+	return filepath.Join(dir, slot+".json"), nil
+}
+
+func Encode(config *cli.Config) File {
+	file := File{
+		Version:     CurrentVersion,
+		SavedAt:     time.Now(),
+		Seed:        config.Seed,
+		Next:        config.Next,
+		Prev:        config.Prev,
+		Location:    config.Location,
+		GameVersion: config.Version,
+		Pokedex:     []api.GetPokemon{},
+		Seen:        config.Seen,
+		Collection:  *config.Collection,
+	}
+	for _, pokemon := range config.Pokedex {
+		file.Pokedex = append(file.Pokedex, pokemon)
+	}
+	sort.Slice(file.Pokedex, func(i, j int) bool {
+		return file.Pokedex[i].ID < file.Pokedex[j].ID
+	})
+	return file
+}
+
+func Decode(data []byte) (File, error) {
+	raw := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return File{}, err
+	}
+	version := 0
+	if v, ok := raw["version"]; ok {
+		if err := json.Unmarshal(v, &version); err != nil {
+			return File{}, err
+		}
+	}
+	if version < 1 || version > CurrentVersion {
+		return File{}, fmt.Errorf("unsupported save version %v", version)
+	}
+	for ; version < CurrentVersion; version++ {
+		if err := migrations[version-1](raw); err != nil {
+			return File{}, fmt.Errorf("migrating save from version %v: %w", version, err)
+		}
+	}
+	raw["version"] = json.RawMessage(fmt.Sprint(CurrentVersion))
+
+	migrated, err := json.Marshal(raw)
+	if err != nil {
+		return File{}, err
+	}
+	file := File{}
+	if err := json.Unmarshal(migrated, &file); err != nil {
+		return File{}, err
+	}
+	return file, nil
+}
+
+func Apply(config *cli.Config, file File) {
+	config.Next = file.Next
+	config.Prev = file.Prev
+	config.Location = file.Location
+	config.Version = file.GameVersion
+	config.Pokedex = make(map[string]api.GetPokemon)
+	config.Seen = make(map[string]int)
+	for name, id := range file.Seen {
+		config.Seen[name] = id
+	}
+	for _, pokemon := range file.Pokedex {
+		config.Pokedex[pokemon.Name] = pokemon
+		config.Seen[pokemon.Name] = pokemon.SpeciesID()
+	}
+	collection := file.Collection
+	if collection.NextID == 0 {
+		collection.NextID = 1
+	}
+	config.Collection = &collection
+}
+
+func Save(config *cli.Config, slot string) error {
+	path, err := slotPath(config.SaveDir, slot)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(config.SaveDir, 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(Encode(config), "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func Load(config *cli.Config, slot string) error {
+	path, err := slotPath(config.SaveDir, slot)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	file, err := Decode(data)
+	if err != nil {
+		return err
+	}
+	Apply(config, file)
+	return nil
+}
+
+func slotArg(config *cli.Config, args []string) string {
+	switch {
+	case len(args) > 0:
+		return args[0]
+	case config.SaveSlot != "":
+		return config.SaveSlot
+	}
+	return DefaultSlot
+}
+
+type slotResult struct {
+	Action  string `json:"action"`
+	Slot    string `json:"slot"`
+	Pokemon int    `json:"pokemon"`
+}
+
+func (r slotResult) Text(w io.Writer) {
+	if r.Action == "save" {
+		fmt.Fprintf(w, "Saved %v pokemon to slot %v\n", r.Pokemon, r.Slot)
+		return
+	}
+	fmt.Fprintf(w, "Loaded %v pokemon from slot %v\n", r.Pokemon, r.Slot)
+}
+
+func CommandSave(ctx context.Context, config *cli.Config, args []string, flags map[string]string) error {
+	slot := slotArg(config, args)
+	if err := Save(config, slot); err != nil {
+		return fmt.Errorf("could not save: %w", err)
+	}
+	config.SaveSlot = slot
+	return config.Out.Render(slotResult{Action: "save", Slot: slot, Pokemon: config.Collection.Len()})
+}
+
+func CommandLoad(ctx context.Context, config *cli.Config, args []string, flags map[string]string) error {
+	slot := slotArg(config, args)
+	if err := Load(config, slot); err != nil {
+		return fmt.Errorf("could not load: %w", err)
+	}
+	config.SaveSlot = slot
+	return config.Out.Render(slotResult{Action: "load", Slot: slot, Pokemon: config.Collection.Len()})
+}
+
+// Resume starts a session from the default slot, which becomes the active
+// slot whether or not it exists yet, so autosave creates it on first exit.
+// A save that fails to load leaves no active slot, so autosave cannot
+// overwrite it.
+func Resume(config *cli.Config) error {
+	if config.SaveDir == "" {
+		return nil
+	}
+	if err := Load(config, DefaultSlot); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("could not load the %v save: %w", DefaultSlot, err)
+	}
+	config.SaveSlot = DefaultSlot
+	return nil
+}
+
+// Autosave writes the current session to the active slot, if there is one.
+func Autosave(config *cli.Config) error {
+	if config.SaveDir == "" || config.SaveSlot == "" {
+		return nil
+	}
+	return Save(config, config.SaveSlot)
+}
@@ -0,0 +1,146 @@
+package savepkg
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/almasx/pokedexcli/internal/api"
+	"github.com/almasx/pokedexcli/internal/cli"
+	"github.com/almasx/pokedexcli/internal/trainer"
+)
+
+func TestSaveLoad(t *testing.T) {
+	caughtAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	pikachu := api.GetPokemon{ID: 25, Name: "pikachu"}
+	config := &cli.Config{
+		Next:       "next-page",
+		Prev:       "prev-page",
+		SaveDir:    t.TempDir(),
+		Pokedex:    map[string]api.GetPokemon{"pikachu": pikachu},
+		Collection: trainer.NewCollection(),
+	}
+	config.Collection.Add(&trainer.Owned{Nickname: "sparky", Level: 12, CaughtAt: caughtAt, Pokemon: pikachu})
+	if err := Save(config, "slot1"); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := &cli.Config{SaveDir: config.SaveDir}
+	if err := Load(loaded, "slot1"); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Next != "next-page" || loaded.Prev != "prev-page" {
+		t.Errorf("expected cursors to round-trip, got %q %q", loaded.Next, loaded.Prev)
+	}
+	if loaded.Pokedex["pikachu"].ID != 25 {
+		t.Errorf("expected pikachu to be in the pokedex, got %v", loaded.Pokedex)
+	}
+	sparky, err := loaded.Collection.Find("sparky")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sparky.Level != 12 || !sparky.CaughtAt.Equal(caughtAt) {
+		t.Errorf("expected level 12 caught at %v, got %+v", caughtAt, sparky)
+	}
+
+	if err := Save(config, "../escape"); err == nil {
+		t.Errorf("expected invalid slot name to be rejected")
+	}
+	if _, err := Decode([]byte(`{"version": 99}`)); err == nil {
+		t.Errorf("expected future save version to be rejected")
+	}
+}
+
+func TestMigrateV1(t *testing.T) {
+	v1 := `{
+		"version": 1,
+		"next": "next-page",
+		"pokemon": [
+			{"caught_at": "2024-01-02T03:04:05Z", "pokemon": {"id": 16, "name": "pidgey"}},
+			{"caught_at": "2024-01-03T03:04:05Z", "pokemon": {"id": 25, "name": "pikachu"}}
+		]
+	}`
+	file, err := Decode([]byte(v1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.Version != CurrentVersion || file.Next != "next-page" {
+		t.Errorf("expected migrated file at version %v, got %+v", CurrentVersion, file)
+	}
+	if len(file.Pokedex) != 2 || len(file.Collection.Party) != 2 {
+		t.Fatalf("expected 2 species and 2 party pokemon, got %v and %v", len(file.Pokedex), len(file.Collection.Party))
+	}
+	if lead := file.Collection.Party[0]; lead.ID != 1 || lead.Pokemon.Name != "pidgey" || lead.Level != 50 {
+		t.Errorf("unexpected lead after migration: %+v", lead)
+	}
+	if file.Collection.NextID != 3 {
+		t.Errorf("expected next id 3, got %v", file.Collection.NextID)
+	}
+}
+
+func TestAutosaveNeedsActiveSlot(t *testing.T) {
+	dir := t.TempDir()
+	saved := &cli.Config{SaveDir: dir, Collection: trainer.NewCollection()}
+	saved.Collection.Add(&trainer.Owned{Level: 5, Pokemon: api.GetPokemon{ID: 16, Name: "pidgey"}})
+	if err := Save(saved, DefaultSlot); err != nil {
+		t.Fatal(err)
+	}
+	path, err := slotPath(dir, DefaultSlot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A session whose save failed to load has no active slot and must leave
+	// the slot alone.
+	fresh := &cli.Config{SaveDir: dir, Collection: trainer.NewCollection()}
+	if err := Autosave(fresh); err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := os.ReadFile(path); string(after) != string(before) {
+		t.Errorf("expected autosave without an active slot to leave %v unchanged", DefaultSlot)
+	}
+
+	fresh.SaveSlot = DefaultSlot
+	if err := Autosave(fresh); err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := os.ReadFile(path); string(after) == string(before) {
+		t.Errorf("expected autosave to write the active slot")
+	}
+}
+
+func TestResume(t *testing.T) {
+	dir := t.TempDir()
+	fresh := &cli.Config{SaveDir: dir, Collection: trainer.NewCollection()}
+	if err := Resume(fresh); err != nil {
+		t.Fatal(err)
+	}
+	if fresh.SaveSlot != DefaultSlot {
+		t.Errorf("expected a first session to autosave to %v, got %q", DefaultSlot, fresh.SaveSlot)
+	}
+
+	fresh.Collection.Add(&trainer.Owned{Level: 5, Pokemon: api.GetPokemon{ID: 16, Name: "pidgey"}})
+	if err := Autosave(fresh); err != nil {
+		t.Fatal(err)
+	}
+	resumed := &cli.Config{SaveDir: dir}
+	if err := Resume(resumed); err != nil {
+		t.Fatal(err)
+	}
+	if resumed.SaveSlot != DefaultSlot || resumed.Collection.Len() != 1 {
+		t.Errorf("expected the next session to resume the pidgey, got %q with %v pokemon", resumed.SaveSlot, resumed.Collection.Len())
+	}
+
+	path, _ := slotPath(dir, DefaultSlot)
+	if err := os.WriteFile(path, []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	broken := &cli.Config{SaveDir: dir}
+	if err := Resume(broken); err == nil || broken.SaveSlot != "" {
+		t.Errorf("expected a broken save to fail without an active slot, got %q, %v", broken.SaveSlot, err)
+	}
+}
@@ -0,0 +1,205 @@
+package trainer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/almasx/pokedexcli/internal/api"
+)
+
+const PartySize = 6
+
+// Owned is one individual pokemon the player has caught.
+type Owned struct {
+	ID       int            `json:"id"`
+	Nickname string         `json:"nickname,omitempty"`
+	Level    int            `json:"level"`
+	Location string         `json:"location,omitempty"`
+	CaughtAt time.Time      `json:"caught_at"`
+	Pokemon  api.GetPokemon `json:"pokemon"`
+}
+
+// Name is the nickname if one was given, otherwise the species name.
+func (o *Owned) Name() string {
+	if o.Nickname != "" {
+		return o.Nickname
+	}
+	return o.Pokemon.Name
+}
+
+func (o *Owned) String() string {
+	if o.Nickname != "" {
+		return fmt.Sprintf("#%v %v (%v) Lv%v", o.ID, o.Nickname, o.Pokemon.Name, o.Level)
+	}
+	return fmt.Sprintf("#%v %v Lv%v", o.ID, o.Pokemon.Name, o.Level)
+}
+
+// Collection holds every owned pokemon: up to six in the party and the rest in the box.
+type Collection struct {
+	NextID int      `json:"next_id"`
+	Party  []*Owned `json:"party"`
+	Box    []*Owned `json:"box"`
+}
+
+func NewCollection() *Collection {
+	return &Collection{NextID: 1}
+}
+
+// Add assigns o an ID and places it in the party, or the box if the party is full.
+func (c *Collection) Add(o *Owned) (inParty bool) {
+	o.ID = c.NextID
+	c.NextID++
+	if len(c.Party) < PartySize {
+		c.Party = append(c.Party, o)
+		return true
+	}
+	c.Box = append(c.Box, o)
+	return false
+}
+
+func (c *Collection) All() []*Owned {
+	all := make([]*Owned, 0, len(c.Party)+len(c.Box))
+	all = append(all, c.Party...)
+	return append(all, c.Box...)
+}
+
+func (c *Collection) Len() int {
+	return len(c.Party) + len(c.Box)
+}
+
+// Lead is the first pokemon in the party, or nil if the party is empty.
+func (c *Collection) Lead() *Owned {
+	if len(c.Party) == 0 {
+		return nil
+	}
+	return c.Party[0]
+}
+
+func (c *Collection) OwnsSpecies(species string) bool {
+	for _, o := range c.All() {
+		if o.Pokemon.Species.Name == species || o.Pokemon.Name == species {
+			return true
+		}
+	}
+	return false
+}
+
+func match(list []*Owned, ref string) []*Owned {
+	id, err := strconv.Atoi(strings.TrimPrefix(ref, "#"))
+	if err == nil {
+		for _, o := range list {
+			if o.ID == id {
+				return []*Owned{o}
+			}
+		}
+		return nil
+	}
+	for _, o := range list {
+		if o.Nickname == ref {
+			return []*Owned{o}
+		}
+	}
+	var matches []*Owned
+	for _, o := range list {
+		if o.Pokemon.Name == ref {
+			matches = append(matches, o)
+		}
+	}
+	return matches
+}
+
+func find(list []*Owned, ref string) (*Owned, error) {
+	matches := match(list, ref)
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("you don't have a pokemon called %s", ref)
+	case 1:
+		return matches[0], nil
+	}
+	return nil, fmt.Errorf("you have %v pokemon called %s, use its #id instead", len(matches), ref)
+}
+
+// Find resolves ref as "#id", a nickname or a species name across party and box.
+func (c *Collection) Find(ref string) (*Owned, error) {
+	return find(c.All(), ref)
+}
+
+func remove(list []*Owned, o *Owned) []*Owned {
+	for i, other := range list {
+		if other == o {
+			return append(list[:i], list[i+1:]...)
+		}
+	}
+	return list
+}
+
+func (c *Collection) Deposit(ref string) (*Owned, error) {
+	o, err := find(c.Party, ref)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.Party) == 1 {
+		return nil, fmt.Errorf("you can't deposit your last party pokemon")
+	}
+	c.Party = remove(c.Party, o)
+	c.Box = append(c.Box, o)
+	return o, nil
+}
+
+func (c *Collection) Withdraw(ref string) (*Owned, error) {
+	o, err := find(c.Box, ref)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.Party) >= PartySize {
+		return nil, fmt.Errorf("your party is full")
+	}
+	c.Box = remove(c.Box, o)
+	c.Party = append(c.Party, o)
+	return o, nil
+}
+
+// Swap exchanges two party members, given as 1-based slots or refs.
+func (c *Collection) Swap(a, b string) error {
+	i, err := c.partySlot(a)
+	if err != nil {
+		return err
+	}
+	j, err := c.partySlot(b)
+	if err != nil {
+		return err
+	}
+	c.Party[i], c.Party[j] = c.Party[j], c.Party[i]
+	return nil
+}
+
+func (c *Collection) partySlot(ref string) (int, error) {
+	if slot, err := strconv.Atoi(ref); err == nil {
+		if slot < 1 || slot > len(c.Party) {
+			return 0, fmt.Errorf("party slot %v is empty", slot)
+		}
+		return slot - 1, nil
+	}
+	o, err := find(c.Party, ref)
+	if err != nil {
+		return 0, err
+	}
+	for i, other := range c.Party {
+		if other == o {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("%s is not in your party", ref)
+}
+
+func (c *Collection) Release(ref string) (*Owned, error) {
+	o, err := c.Find(ref)
+	if err != nil {
+		return nil, err
+	}
+	c.Party = remove(c.Party, o)
+	c.Box = remove(c.Box, o)
+	return o, nil
+}
@@ -0,0 +1,52 @@
+package trainer
+
+import (
+	"testing"
+
+	"github.com/almasx/pokedexcli/internal/api"
+)
+
+func TestCollection(t *testing.T) {
+	c := NewCollection()
+	for i := 0; i < PartySize+1; i++ {
+		inParty := c.Add(&Owned{Level: 5, Pokemon: api.GetPokemon{Name: "pidgey"}})
+		if inParty != (i < PartySize) {
+			t.Errorf("pokemon %v: expected inParty %v, got %v", i, i < PartySize, inParty)
+		}
+	}
+	if len(c.Party) != PartySize || len(c.Box) != 1 {
+		t.Fatalf("expected %v in party and 1 in box, got %v and %v", PartySize, len(c.Party), len(c.Box))
+	}
+
+	if _, err := c.Find("pidgey"); err == nil {
+		t.Errorf("expected ambiguous species name to be rejected")
+	}
+	if _, err := c.Withdraw("#7"); err == nil {
+		t.Errorf("expected withdraw into a full party to fail")
+	}
+
+	first, err := c.Find("#1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	first.Nickname = "birdie"
+	if err := c.Swap("birdie", "6"); err != nil {
+		t.Fatal(err)
+	}
+	if c.Party[5] != first || c.Lead().ID != 6 {
+		t.Errorf("expected #1 and #6 to swap, party lead is #%v", c.Lead().ID)
+	}
+
+	if _, err := c.Deposit("birdie"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Withdraw("#7"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Release("birdie"); err != nil {
+		t.Fatal(err)
+	}
+	if c.Len() != PartySize {
+		t.Errorf("expected %v pokemon after release, got %v", PartySize, c.Len())
+	}
+}
@@ -0,0 +1,116 @@
+package types
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/almasx/pokedexcli/internal/api"
+)
+
+// Names lists the 18 battle types in national (PokeAPI ID) order.
+var Names = []string{
+	"normal", "fighting", "flying", "poison", "ground", "rock",
+	"bug", "ghost", "steel", "fire", "water", "grass",
+	"electric", "psychic", "ice", "dragon", "dark", "fairy",
+}
+
+func Index(name string) (int, bool) {
+	for i, typeName := range Names {
+		if typeName == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// Chart is the attacker x defender damage multiplier matrix.
+type Chart [18][18]float64
+
+// NewChart builds a chart from the damage relations of each type. Types that
+// are missing from types leave their rows at neutral damage.
+func NewChart(types []api.GetType) *Chart {
+	chart := &Chart{}
+	for i := range chart {
+		for j := range chart[i] {
+			chart[i][j] = 1
+		}
+	}
+
+	set := func(attacker, defender string, multiplier float64) {
+		a, ok := Index(attacker)
+		if !ok {
+			return
+		}
+		d, ok := Index(defender)
+		if !ok {
+			return
+		}
+		chart[a][d] = multiplier
+	}
+	for _, t := range types {
+		relations := t.DamageRelations
+		for _, other := range relations.DoubleDamageTo {
+			set(t.Name, other.Name, 2)
+		}
+		for _, other := range relations.HalfDamageTo {
+			set(t.Name, other.Name, 0.5)
+		}
+		for _, other := range relations.NoDamageTo {
+			set(t.Name, other.Name, 0)
+		}
+		for _, other := range relations.DoubleDamageFrom {
+			set(other.Name, t.Name, 2)
+		}
+		for _, other := range relations.HalfDamageFrom {
+			set(other.Name, t.Name, 0.5)
+		}
+		for _, other := range relations.NoDamageFrom {
+			set(other.Name, t.Name, 0)
+		}
+	}
+	return chart
+}
+
+// Effectiveness returns the combined multiplier of an attacking type against
+// one or two defending types.
+func (c *Chart) Effectiveness(attacker string, defenders ...string) (float64, error) {
+	a, ok := Index(attacker)
+	if !ok {
+		return 0, fmt.Errorf("unknown type %q", attacker)
+	}
+	multiplier := 1.0
+	for _, defender := range defenders {
+		d, ok := Index(defender)
+		if !ok {
+			return 0, fmt.Errorf("unknown type %q", defender)
+		}
+		multiplier *= c[a][d]
+	}
+	return multiplier, nil
+}
+
+// LoadChart builds the chart from the 18 types fetched through client. The
+// types come from the client's cache after the first load, so the chart
+// follows the cache policy for type/* rather than living for the process.
+func LoadChart(ctx context.Context, client *api.Client) (*Chart, error) {
+	var types []api.GetType
+	for _, name := range Names {
+		t, err := client.Type(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, t)
+	}
+	return NewChart(types), nil
+}
+
+// Of returns the current type names of a pokemon, in slot order.
+func Of(pokemon api.GetPokemon) []string {
+	names := make([]string, len(pokemon.Types))
+	for _, t := range pokemon.Types {
+		if t.Slot >= 1 && t.Slot <= len(names) {
+			names[t.Slot-1] = t.Type.Name
+		}
+	}
+	return names
+}
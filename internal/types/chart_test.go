@@ -0,0 +1,63 @@
+package types
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/almasx/pokedexcli/internal/api"
+	"github.com/almasx/pokedexcli/internal/apitest"
+)
+
+func TestEffectiveness(t *testing.T) {
+	electric := api.GetType{Name: "electric"}
+	electric.DamageRelations.DoubleDamageTo = []api.NamedResource{{Name: "water"}, {Name: "flying"}}
+	electric.DamageRelations.NoDamageTo = []api.NamedResource{{Name: "ground"}}
+	grass := api.GetType{Name: "grass"}
+	grass.DamageRelations.HalfDamageFrom = []api.NamedResource{{Name: "water"}}
+
+	chart := NewChart([]api.GetType{electric, grass})
+
+	cases := []struct {
+		attacker  string
+		defenders []string
+		expected  float64
+	}{
+		{"electric", []string{"water", "flying"}, 4},
+		{"electric", []string{"water", "ground"}, 0},
+		{"electric", []string{"fire"}, 1},
+		{"water", []string{"grass"}, 0.5},
+	}
+	for _, c := range cases {
+		actual, err := chart.Effectiveness(c.attacker, c.defenders...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual != c.expected {
+			t.Errorf("%v vs %v: expected %v, got %v", c.attacker, c.defenders, c.expected, actual)
+		}
+	}
+
+	if _, err := chart.Effectiveness("shadow", "normal"); err == nil {
+		t.Errorf("expected error for unknown type")
+	}
+}
+
+func TestLoadChartUsesCache(t *testing.T) {
+	var requests atomic.Int32
+	client := apitest.NewClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		fmt.Fprintf(w, `{"name": %q}`, strings.TrimPrefix(r.URL.Path, "/type/"))
+	})
+	for i := 0; i < 2; i++ {
+		if _, err := LoadChart(context.Background(), client); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := requests.Load(); n != int32(len(Names)) {
+		t.Errorf("expected each type to be fetched once, got %v requests", n)
+	}
+}
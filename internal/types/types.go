@@ -0,0 +1,136 @@
+package types
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/almasx/pokedexcli/internal/cli"
+)
+
+func describe(multiplier float64) string {
+	switch {
+	case multiplier == 0:
+		return "no effect"
+	case multiplier > 1:
+		return "super effective"
+	case multiplier < 1:
+		return "not very effective"
+	}
+	return "normal damage"
+}
+
+type matchup struct {
+	Attacker      string   `json:"attacker"`
+	Defender      string   `json:"defender"`
+	DefenderTypes []string `json:"defender_types"`
+	Multiplier    float64  `json:"multiplier"`
+}
+
+type matchupList []matchup
+
+func (l matchupList) Text(w io.Writer) {
+	for _, m := range l {
+		fmt.Fprintf(w, "%v vs %v (%v): %gx - %v\n", m.Attacker, m.Defender, strings.Join(m.DefenderTypes, "/"), m.Multiplier, describe(m.Multiplier))
+	}
+}
+
+func (l matchupList) Table() ([]string, [][]string) {
+	rows := make([][]string, len(l))
+	for i, m := range l {
+		rows[i] = []string{m.Attacker, m.Defender, strings.Join(m.DefenderTypes, "/"), fmt.Sprint(m.Multiplier)}
+	}
+	return []string{"attacker", "defender", "defender_types", "multiplier"}, rows
+}
+
+func CommandMatchup(ctx context.Context, config *cli.Config, args []string, flags map[string]string) error {
+
+	chart, err := LoadChart(ctx, config.Client)
+	if err != nil {
+		return err
+	}
+
+	attackers := []string{args[0]}
+	if _, ok := Index(args[0]); !ok {
+		attacker, err := config.Client.Pokemon(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		attackers = Of(attacker)
+	}
+	defender, err := config.Client.Pokemon(ctx, args[1])
+	if err != nil {
+		return err
+	}
+	defenderTypes := Of(defender)
+
+	var result matchupList
+	for _, attacker := range attackers {
+		multiplier, err := chart.Effectiveness(attacker, defenderTypes...)
+		if err != nil {
+			return err
+		}
+		result = append(result, matchup{Attacker: attacker, Defender: defender.Name, DefenderTypes: defenderTypes, Multiplier: multiplier})
+	}
+	return config.Out.Render(result)
+}
+
+type weakness struct {
+	Multiplier float64  `json:"multiplier"`
+	Types      []string `json:"types"`
+}
+
+type weaknessResult struct {
+	Pokemon    string     `json:"pokemon"`
+	Types      []string   `json:"types"`
+	Multiplier []weakness `json:"multipliers"`
+}
+
+func (r weaknessResult) Text(w io.Writer) {
+	fmt.Fprintf(w, "%v (%v)\n", r.Pokemon, strings.Join(r.Types, "/"))
+	for _, group := range r.Multiplier {
+		fmt.Fprintf(w, "  %gx: %v\n", group.Multiplier, strings.Join(group.Types, ", "))
+	}
+}
+
+func (r weaknessResult) Table() ([]string, [][]string) {
+	var rows [][]string
+	for _, group := range r.Multiplier {
+		for _, attacker := range group.Types {
+			rows = append(rows, []string{r.Pokemon, attacker, fmt.Sprint(group.Multiplier)})
+		}
+	}
+	return []string{"pokemon", "attacker", "multiplier"}, rows
+}
+
+func CommandWeaknesses(ctx context.Context, config *cli.Config, args []string, flags map[string]string) error {
+
+	chart, err := LoadChart(ctx, config.Client)
+	if err != nil {
+		return err
+	}
+	pokemon, err := config.Client.Pokemon(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	defenderTypes := Of(pokemon)
+
+	groups := map[float64][]string{}
+	for _, attacker := range Names {
+		multiplier, err := chart.Effectiveness(attacker, defenderTypes...)
+		if err != nil {
+			return err
+		}
+		groups[multiplier] = append(groups[multiplier], attacker)
+	}
+
+	result := weaknessResult{Pokemon: pokemon.Name, Types: defenderTypes, Multiplier: []weakness{}}
+	for _, multiplier := range []float64{4, 2, 0.5, 0.25, 0} {
+		if len(groups[multiplier]) == 0 {
+			continue
+		}
+		result.Multiplier = append(result.Multiplier, weakness{Multiplier: multiplier, Types: groups[multiplier]})
+	}
+	return config.Out.Render(result)
+}
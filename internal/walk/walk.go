@@ -0,0 +1,187 @@
+package walk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/almasx/pokedexcli/internal/api"
+	"github.com/almasx/pokedexcli/internal/battle"
+	"github.com/almasx/pokedexcli/internal/cli"
+	explorepkg "github.com/almasx/pokedexcli/internal/explore"
+	"github.com/almasx/pokedexcli/internal/pokemon"
+)
+
+const defaultMethod = "walk"
+
+// Pick chooses an encounter weighted by its chance and a level within its
+// range. It returns false if there is nothing to encounter.
+func Pick(encounters []explorepkg.Encounter, rng *rand.Rand) (explorepkg.Encounter, int, bool) {
+	total := 0
+	for _, e := range encounters {
+		total += e.Chance
+	}
+	if total <= 0 {
+		return explorepkg.Encounter{}, 0, false
+	}
+
+	roll := rng.Intn(total)
+	for _, e := range encounters {
+		if roll < e.Chance {
+			return e, e.MinLevel + rng.Intn(e.MaxLevel-e.MinLevel+1), true
+		}
+		roll -= e.Chance
+	}
+	return explorepkg.Encounter{}, 0, false
+}
+
+// encounterRate is the percentage chance that walking with method triggers an
+// encounter at all. Areas without a listed rate always produce one.
+func encounterRate(area api.GetLocationAreaPokemons, version, method string) int {
+	for _, rate := range area.EncounterMethodRates {
+		if rate.EncounterMethod.Name != method {
+			continue
+		}
+		for _, detail := range rate.VersionDetails {
+			if detail.Version.Name == version {
+				return detail.Rate
+			}
+		}
+	}
+	return 100
+}
+
+func available(encounters []explorepkg.Encounter, field func(explorepkg.Encounter) string) string {
+	seen := map[string]bool{}
+	var names []string
+	for _, e := range encounters {
+		if name := field(e); !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+type walkResult struct {
+	Location string `json:"location"`
+	Pokemon  string `json:"pokemon,omitempty"`
+	Level    int    `json:"level,omitempty"`
+	// Outcome is nothing, ran, caught, or how the battle ended.
+	Outcome string `json:"outcome"`
+}
+
+func (r walkResult) Text(w io.Writer) {
+	switch r.Outcome {
+	case "nothing":
+		fmt.Fprintln(w, "You looked around, but nothing appeared.")
+	case "ran":
+		fmt.Fprintln(w, "Got away safely!")
+	}
+}
+
+func CommandWalk(ctx context.Context, config *cli.Config, args []string, flags map[string]string) error {
+	if config.Location == "" {
+		return fmt.Errorf("you need to goto a location area first")
+	}
+
+	area, err := config.Client.LocationArea(ctx, config.Location)
+	if err != nil {
+		return err
+	}
+	all := explorepkg.Encounters(area, "", "")
+	if len(all) == 0 {
+		return fmt.Errorf("there are no wild pokemon in %s", config.Location)
+	}
+
+	version := config.Version
+	if flags["version"] != "" {
+		version = flags["version"]
+	}
+	if version == "" || len(explorepkg.Encounters(area, version, "")) == 0 {
+		versions := available(all, func(e explorepkg.Encounter) string { return e.Version })
+		return fmt.Errorf("no encounters for version %q, choose one with --version: %s", version, versions)
+	}
+	config.Version = version
+
+	method := defaultMethod
+	if flags["method"] != "" {
+		method = flags["method"]
+	}
+	encounters := explorepkg.Encounters(area, version, method)
+	if len(encounters) == 0 {
+		methods := available(explorepkg.Encounters(area, version, ""), func(e explorepkg.Encounter) string { return e.Method })
+		return fmt.Errorf("no %s encounters here in %s, try --method %s", method, version, methods)
+	}
+
+	result := walkResult{Location: config.Location, Outcome: "nothing"}
+	if config.Rand.Intn(100) >= encounterRate(area, version, method) {
+		return config.Out.Render(result)
+	}
+	encounter, level, ok := Pick(encounters, config.Rand)
+	if !ok {
+		return config.Out.Render(result)
+	}
+
+	wild, err := config.Client.Pokemon(ctx, encounter.Pokemon)
+	if err != nil {
+		return err
+	}
+	config.MarkSeen(wild.Name, wild.SpeciesID())
+	result.Pokemon = wild.Name
+	result.Level = level
+	config.Out.Printf("A wild %v (Lv%v) appeared!\n", wild.Name, level)
+
+	for {
+		line, err := config.Input.ReadLine("battle, catch [ball] or run > ")
+		if err == io.EOF {
+			config.Out.Println()
+			result.Outcome = "abandoned"
+			return config.Out.Render(result)
+		}
+		if err != nil {
+			return err
+		}
+		words := strings.Fields(strings.ToLower(line))
+		if len(words) == 0 {
+			continue
+		}
+		switch words[0] {
+		case "battle", "fight":
+			fought, err := battle.Start(ctx, config, wild, level)
+			if err != nil {
+				return err
+			}
+			result.Outcome = fought.Outcome
+			return config.Out.Render(result)
+		case "catch", "ball":
+			name := ""
+			if len(words) > 1 {
+				name = words[1]
+			}
+			ball, err := pokemon.LookupBall(name)
+			if err != nil {
+				config.Out.Println(err)
+				continue
+			}
+			thrown, err := pokemon.Throw(ctx, config, wild, pokemon.NewWild(wild, level), ball)
+			if err != nil {
+				return err
+			}
+			thrown.Text(config.Out.Log())
+			if thrown.Caught {
+				result.Outcome = "caught"
+				return config.Out.Render(result)
+			}
+		case "run":
+			result.Outcome = "ran"
+			return config.Out.Render(result)
+		default:
+			config.Out.Println("choose battle, catch [ball] or run")
+		}
+	}
+}
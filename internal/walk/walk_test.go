@@ -0,0 +1,39 @@
+package walk
+
+import (
+	"math/rand"
+	"testing"
+
+	explorepkg "github.com/almasx/pokedexcli/internal/explore"
+)
+
+func TestPick(t *testing.T) {
+	encounters := []explorepkg.Encounter{
+		{Pokemon: "pidgey", MinLevel: 2, MaxLevel: 5, Chance: 70},
+		{Pokemon: "rattata", MinLevel: 3, MaxLevel: 3, Chance: 30},
+		{Pokemon: "mew", MinLevel: 5, MaxLevel: 5, Chance: 0},
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	counts := map[string]int{}
+	for i := 0; i < 10000; i++ {
+		e, level, ok := Pick(encounters, rng)
+		if !ok {
+			t.Fatal("expected an encounter")
+		}
+		if level < e.MinLevel || level > e.MaxLevel {
+			t.Errorf("%v level %v outside %v-%v", e.Pokemon, level, e.MinLevel, e.MaxLevel)
+		}
+		counts[e.Pokemon]++
+	}
+	if counts["mew"] != 0 {
+		t.Errorf("expected a zero-chance pokemon never to appear, got %v", counts["mew"])
+	}
+	if counts["pidgey"] < 6500 || counts["pidgey"] > 7500 {
+		t.Errorf("expected pidgey roughly 70%% of the time, got %v/10000", counts["pidgey"])
+	}
+
+	if _, _, ok := Pick(nil, rng); ok {
+		t.Errorf("expected no encounter from an empty table")
+	}
+}
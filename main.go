@@ -1,23 +1,42 @@
 package main
 
 import (
-	"bufio"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime/debug"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/almasx/pokedexcli/internal/api"
+	"github.com/almasx/pokedexcli/internal/battle"
+	cachepkg "github.com/almasx/pokedexcli/internal/cache"
 	"github.com/almasx/pokedexcli/internal/cli"
+	"github.com/almasx/pokedexcli/internal/evolution"
 	explorepkg "github.com/almasx/pokedexcli/internal/explore"
 	mappkg "github.com/almasx/pokedexcli/internal/map"
+	"github.com/almasx/pokedexcli/internal/output"
+	"github.com/almasx/pokedexcli/internal/party"
 	"github.com/almasx/pokedexcli/internal/pokecache"
 	"github.com/almasx/pokedexcli/internal/pokemon"
+	"github.com/almasx/pokedexcli/internal/prefetch"
+	"github.com/almasx/pokedexcli/internal/registry"
+	savepkg "github.com/almasx/pokedexcli/internal/save"
+	"github.com/almasx/pokedexcli/internal/trainer"
+	"github.com/almasx/pokedexcli/internal/types"
+	"github.com/almasx/pokedexcli/internal/walk"
 )
 
 func cleanInput(text string) []string {
 	var res []string
-	for _, value := range strings.Split(text, " ") {
+	for _, value := range strings.Fields(text) {
 		if value != "" {
 			res = append(res, value)
 		}
@@ -25,102 +44,458 @@ func cleanInput(text string) []string {
 	return res
 }
 
-func commandExit(config *cli.Config, args []string) error {
-	fmt.Println("Closing the Pokedex... Goodbye!")
-	os.Exit(0)
+const goodbye = "Closing the Pokedex... Goodbye!"
+
+func commandExit(ctx context.Context, config *cli.Config, args []string, flags map[string]string) error {
+	config.Out.Println(goodbye)
+	shutdown(config, 0)
 	return nil
 }
 
-func commandHelp(config *cli.Config, args []string) error {
-	fmt.Println("Welcome to the Pokedex!")
-	fmt.Println("Usage:")
-	fmt.Println("")
-	fmt.Println("help - Displays a help message")
-	fmt.Println("exit - Exit the Pokedex")
-	fmt.Println("map - Show the map of the Pokemon world")
-	fmt.Println("mapb - Show the previous page of the map")
-	fmt.Println("explore <location_area> - Explore a location area")
-	fmt.Println("catch <pokemon> - Catch a pokemon")
-	fmt.Println("inspect <pokemon> - Inspect a pokemon")
-	fmt.Println("pokedex - Show the pokedex")
-	return nil
+type seedResult struct {
+	Seed int64 `json:"seed"`
+	Set  bool  `json:"set"`
 }
 
-type cliCommand struct {
-	name        string
-	description string
-	callback    func(*cli.Config, []string) error
-}
-
-var commands = map[string]cliCommand{
-	"exit": {
-		name:        "exit",
-		description: "Exit the Pokedex",
-		callback:    commandExit,
-	},
-	"help": {
-		name:        "help",
-		description: "Show all commands",
-		callback:    commandHelp,
-	},
-	"map": {
-		name:        "map",
-		description: "Show the map",
-		callback:    mappkg.CommandMap,
-	},
-	"mapb": {
-		name:        "mapb",
-		description: "Show the previous map",
-		callback:    mappkg.CommandMapb,
-	},
-	"explore": {
-		name:        "explore",
-		description: "Explore the map",
-		callback:    explorepkg.CommandExplore,
-	},
-	"catch": {
-		name:        "catch",
-		description: "Catch a pokemon",
-		callback:    pokemon.CommandCatch,
-	},
-	"inspect": {
-		name:        "inspect",
-		description: "Inspect a pokemon",
-		callback:    pokemon.CommandInspect,
-	},
-	"pokedex": {
-		name:        "pokedex",
-		description: "Show the pokedex",
-		callback:    pokemon.CommandPokedex,
-	},
+func (r seedResult) Text(w io.Writer) {
+	if r.Set {
+		fmt.Fprintln(w, "Seed set to", r.Seed)
+		return
+	}
+	fmt.Fprintln(w, "Current seed:", r.Seed)
 }
 
-func main() {
-	scanner := bufio.NewScanner(os.Stdin)
-	cache := pokecache.NewCache(time.Second * 10)
-	var user_input string
+func commandSeed(ctx context.Context, config *cli.Config, args []string, flags map[string]string) error {
+	if len(args) == 0 {
+		return config.Out.Render(seedResult{Seed: config.Seed})
+	}
+	seed, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("seed must be an integer")
+	}
+	config.SetSeed(seed)
+	return config.Out.Render(seedResult{Seed: seed, Set: true})
+}
 
-	config := cli.Config{
-		Next:    "",
-		Prev:    "",
-		Cache:   cache,
-		Pokedex: make(map[string]api.GetPokemon),
+type setting struct {
+	Setting string `json:"setting"`
+	Value   string `json:"value"`
+}
+
+func (s setting) Text(w io.Writer) {
+	fmt.Fprintf(w, "%v set to %v\n", s.Setting, s.Value)
+}
+
+func commandSet(ctx context.Context, config *cli.Config, args []string, flags map[string]string) error {
+	switch args[0] {
+	case "output":
+		format, err := output.ParseFormat(args[1])
+		if err != nil {
+			return err
+		}
+		config.Out.Format = format
+		return config.Out.Render(setting{Setting: "output", Value: string(format)})
 	}
+	return fmt.Errorf("unknown setting %q", args[0])
+}
 
-	for {
-		fmt.Printf("Pokedex > ")
-		scanner.Scan()
-		user_input = scanner.Text()
-		words := cleanInput(user_input)
+var commands = newRegistry()
 
-		if len(words) == 0 {
-			continue
+func newRegistry() *registry.Registry {
+	r := registry.New()
+	for _, c := range []*registry.Command{
+		r.HelpCommand(),
+		{
+			Name:     "exit",
+			Summary:  "Exit the Pokedex",
+			Help:     "Your session is autosaved before exiting, to the default slot unless save or load picked another.",
+			Callback: commandExit,
+		},
+		{
+			Name:     "map",
+			Flags:    []registry.Flag{{Name: "region", Value: "region", Help: "show a region's locations and areas instead"}},
+			Summary:  "Show the next page of location areas",
+			Callback: mappkg.CommandMap,
+		},
+		{
+			Name:     "mapb",
+			Summary:  "Show the previous page of location areas",
+			Callback: mappkg.CommandMapb,
+		},
+		{
+			Name:     "goto",
+			Args:     []registry.Arg{{Name: "location", Complete: areaNames}},
+			Summary:  "Move to a location or location area",
+			Help:     "A location with a single area moves you straight into that area.",
+			Callback: mappkg.CommandGoto,
+		},
+		{
+			Name: "explore",
+			Args: []registry.Arg{{Name: "location_area", Optional: true, Complete: areaNames}},
+			Flags: []registry.Flag{
+				{Name: "version", Value: "version", Help: "only show encounters in this game version"},
+				{Name: "method", Value: "method", Help: "only show encounters with this method, e.g. walk or surf"},
+				{Name: "summary", Help: "collapse versions into one row per pokemon and method"},
+			},
+			Summary:  "Explore a location area, or where you are",
+			Callback: explorepkg.CommandExplore,
+		},
+		{
+			Name:    "walk",
+			Aliases: []string{"encounter"},
+			Flags: []registry.Flag{
+				{Name: "version", Value: "version", Help: "game version to use, remembered for later walks"},
+				{Name: "method", Value: "method", Help: "encounter method, walk by default"},
+			},
+			Summary:  "Look for wild pokemon where you are",
+			Help:     "When a wild pokemon appears you can battle it, throw a ball or run.",
+			Callback: walk.CommandWalk,
+		},
+		{
+			Name: "catch",
+			Args: []registry.Arg{{Name: "pokemon", Complete: speciesNames}},
+			Flags: []registry.Flag{
+				{Name: "ball", Value: "ball", Help: "poke, great, ultra or master"},
+				{Name: "hp", Value: "percent", Help: "the pokemon's remaining HP, 1 to 100 (default 100)"},
+				{Name: "status", Value: "status", Help: "sleep, freeze, paralysis, poison or burn"},
+			},
+			Summary:  "Catch a pokemon",
+			Callback: pokemon.CommandCatch,
+		},
+		{
+			Name:     "inspect",
+			Args:     []registry.Arg{{Name: "pokemon", Complete: dexNames}},
+			Summary:  "Inspect a pokemon",
+			Help:     "Accepts a party or box pokemon by #id or nickname, or a caught species.",
+			Callback: pokemon.CommandInspect,
+		},
+		{
+			Name:     "battle",
+			Args:     []registry.Arg{{Name: "pokemon", Complete: speciesNames}},
+			Summary:  "Battle a wild pokemon with your lead pokemon",
+			Callback: battle.CommandBattle,
+		},
+		{
+			Name: "pokedex",
+			Flags: []registry.Flag{
+				{Name: "seen", Help: "only species you have seen but not caught"},
+				{Name: "caught", Help: "only species you have caught"},
+				{Name: "missing", Help: "species you have not caught yet"},
+				{Name: "generation", Value: "n", Help: "limit to one generation"},
+				{Name: "stats", Help: "completion by generation and region"},
+			},
+			Summary:  "Show the pokedex",
+			Callback: pokemon.CommandPokedex,
+		},
+		{
+			Name:     "party",
+			Summary:  "Show your party",
+			Callback: party.CommandParty,
+		},
+		{
+			Name:     "box",
+			Summary:  "Show the pokemon in your box",
+			Callback: party.CommandBox,
+		},
+		{
+			Name:     "deposit",
+			Args:     []registry.Arg{{Name: "pokemon", Complete: ownedNames}},
+			Summary:  "Move a party pokemon to the box",
+			Callback: party.CommandDeposit,
+		},
+		{
+			Name:     "withdraw",
+			Args:     []registry.Arg{{Name: "pokemon", Complete: ownedNames}},
+			Summary:  "Move a boxed pokemon to the party",
+			Callback: party.CommandWithdraw,
+		},
+		{
+			Name:     "swap",
+			Args:     []registry.Arg{{Name: "pokemon|slot", Complete: ownedNames}, {Name: "pokemon|slot", Complete: ownedNames}},
+			Summary:  "Reorder your party",
+			Callback: party.CommandSwap,
+		},
+		{
+			Name:     "nickname",
+			Args:     []registry.Arg{{Name: "pokemon", Complete: ownedNames}, {Name: "name"}},
+			Summary:  "Give a pokemon a nickname",
+			Callback: party.CommandNickname,
+		},
+		{
+			Name:     "release",
+			Args:     []registry.Arg{{Name: "pokemon", Complete: ownedNames}},
+			Summary:  "Release a pokemon",
+			Callback: party.CommandRelease,
+		},
+		{
+			Name:     "evolution",
+			Args:     []registry.Arg{{Name: "pokemon", Complete: speciesNames}},
+			Summary:  "Show a pokemon's evolution chain",
+			Callback: evolution.CommandEvolution,
+		},
+		{
+			Name: "evolve",
+			Args: []registry.Arg{{Name: "pokemon", Complete: ownedNames}},
+			Flags: []registry.Flag{
+				{Name: "item", Value: "item", Help: "use an evolution item, e.g. thunder-stone"},
+				{Name: "into", Value: "pokemon", Help: "pick one of several possible evolutions"},
+			},
+			Summary:  "Evolve a caught pokemon",
+			Callback: evolution.CommandEvolve,
+		},
+		{
+			Name:     "matchup",
+			Args:     []registry.Arg{{Name: "type|pokemon", Complete: typeOrSpeciesNames}, {Name: "pokemon", Complete: speciesNames}},
+			Summary:  "Show type effectiveness against a pokemon",
+			Callback: types.CommandMatchup,
+		},
+		{
+			Name:     "weaknesses",
+			Args:     []registry.Arg{{Name: "pokemon", Complete: speciesNames}},
+			Summary:  "Show what a pokemon is weak and resistant to",
+			Callback: types.CommandWeaknesses,
+		},
+		{
+			Name:     "cache",
+			Args:     []registry.Arg{{Name: "clear|stats|policies", Complete: fixed("clear", "stats", "policies")}},
+			Summary:  "Manage the response cache",
+			Help:     "Cache policies come from the config file, then the built-in defaults; the first match wins.",
+			Callback: cachepkg.CommandCache,
+		},
+		{
+			Name: "prefetch",
+			Args: []registry.Arg{
+				{Name: "region|generation|area", Complete: fixed("region", "generation", "area")},
+				{Name: "name"},
+			},
+			Flags:   []registry.Flag{{Name: "workers", Value: "n", Help: "requests to run at once, 4 by default"}},
+			Summary: "Warm the cache with a whole region, generation or area",
+			Help: "Fetches locations, areas, the pokemon found there and their species, within the --rate limit. " +
+				"Resources already cached are skipped, so an interrupted prefetch resumes when run again.",
+			Callback: prefetch.CommandPrefetch,
+		},
+		{
+			Name:     "save",
+			Args:     []registry.Arg{{Name: "slot", Optional: true}},
+			Summary:  "Save caught pokemon and map position",
+			Callback: savepkg.CommandSave,
+		},
+		{
+			Name:     "load",
+			Args:     []registry.Arg{{Name: "slot", Optional: true}},
+			Summary:  "Load a saved session",
+			Callback: savepkg.CommandLoad,
+		},
+		{
+			Name:     "seed",
+			Args:     []registry.Arg{{Name: "number", Optional: true}},
+			Summary:  "Show or set the random seed",
+			Callback: commandSeed,
+		},
+		{
+			Name:     "set",
+			Args:     []registry.Arg{{Name: "setting", Complete: fixed("output")}, {Name: "value", Complete: fixed("text", "json", "yaml", "csv")}},
+			Summary:  "Change a setting",
+			Help:     "set output <text|json|yaml|csv> chooses how results are printed.",
+			Callback: commandSet,
+		},
+	} {
+		r.Register(c)
+	}
+	return r
+}
+
+var errInterrupted = errors.New("interrupted")
+
+// runCommand recovers from a panicking callback so the session survives, and
+// logs the seed alongside the stack so the crash can be replayed.
+func runCommand(ctx context.Context, command *registry.Command, config *cli.Config, args []string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "crash in %v (seed %v): %v\n%s", command.Name, config.Seed, r, debug.Stack())
+			err = fmt.Errorf("%v crashed: %v", command.Name, r)
 		}
-		command, exists := commands[strings.ToLower(words[0])]
-		if !exists {
-			fmt.Println("Unknown command")
+	}()
+	args, flags, err := command.Parse(args)
+	if err != nil {
+		return err
+	}
+	return command.Callback(ctx, config, args, flags)
+}
+
+// outputOverride removes a per-command "-o <format>" or "--output <format>"
+// from args and returns the format it asked for.
+func outputOverride(args []string) ([]string, output.Format, error) {
+	for i, arg := range args {
+		name, value, hasValue := strings.Cut(arg, "=")
+		if name != "-o" && name != "--output" {
 			continue
 		}
-		command.callback(&config, words[1:])
+		rest := append([]string{}, args[:i]...)
+		if !hasValue {
+			if i+1 >= len(args) {
+				return nil, "", fmt.Errorf("%s requires a format", name)
+			}
+			value = args[i+1]
+			i++
+		}
+		format, err := output.ParseFormat(value)
+		if err != nil {
+			return nil, "", err
+		}
+		return append(rest, args[i+1:]...), format, nil
+	}
+	return args, "", nil
+}
+
+// execute runs one line of input. Blank lines and #comments are skipped.
+// Errors are reported through config.Out as well as returned.
+func execute(ctx context.Context, config *cli.Config, line string) error {
+	return executeWith(ctx, commands, config, line)
+}
+
+// executeWith is execute with commands looked up in r.
+func executeWith(ctx context.Context, r *registry.Registry, config *cli.Config, line string) error {
+	words := cleanInput(line)
+	if len(words) == 0 || strings.HasPrefix(words[0], "#") {
+		return nil
 	}
-}	
+	command, exists := r.Lookup(words[0])
+	if !exists {
+		err := r.UnknownError(words[0])
+		config.Out.Error(err)
+		return err
+	}
+
+	args, format, err := outputOverride(words[1:])
+	if err != nil {
+		config.Out.Error(err)
+		return err
+	}
+	if format != "" {
+		defer func(previous output.Format) { config.Out.Format = previous }(config.Out.Format)
+		config.Out.Format = format
+	}
+
+	if err := runCommand(ctx, command, config, args); err != nil {
+		if errors.Is(err, context.Canceled) {
+			err = errInterrupted
+		}
+		config.Out.Error(err)
+		return err
+	}
+	return nil
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+const diskCacheMaxBytes = 64 << 20
+
+func newDiskCache() (*pokecache.DiskCache, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return pokecache.NewDiskCache(filepath.Join(dir, "pokedexcli"), diskCacheMaxBytes)
+}
+
+// historyPath is where the interactive line editor keeps its history, or ""
+// when there is no config directory.
+func historyPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "pokedexcli", "history")
+}
+
+func newClient(cache *pokecache.Cache, offline, record bool, dataDir string) (*api.Client, error) {
+	client := api.NewClient(cache)
+	client.DataDir = dataDir
+	switch {
+	case offline && record:
+		return nil, fmt.Errorf("--offline and --record cannot be combined")
+	case record:
+		client.Mode = api.ModeRecord
+	case offline || os.Getenv("POKEDEX_DATA_DIR") != "":
+		client.Mode = api.ModeOffline
+	}
+	if client.Mode != api.ModeOnline && dataDir == "" {
+		return nil, fmt.Errorf("a data directory is required: set --data-dir or POKEDEX_DATA_DIR")
+	}
+	return client, nil
+}
+
+func main() {
+	offline := flag.Bool("offline", false, "serve all lookups from the JSON fixtures in the data directory")
+	record := flag.Bool("record", false, "record API responses into the data directory")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "seed for catches, encounters and battles")
+	outputFormat := flag.String("output", "text", "result format: text, json, yaml or csv")
+	oneShot := flag.String("c", "", "run the given commands, separated by ';', and exit")
+	timeout := flag.Duration("timeout", api.DefaultTimeout, "give up on an API request after this long, 0 for no limit")
+	rate := flag.Float64("rate", api.DefaultRequestsPerSecond, "maximum API requests per second, 0 for no limit")
+	dataDir := flag.String("data-dir", os.Getenv("POKEDEX_DATA_DIR"), "directory of PokeAPI fixtures laid out like the API paths")
+	settingsPath := flag.String("config", "", "JSON config file (default: config.json in the user config directory)")
+	flag.Parse()
+
+	path, explicit := *settingsPath, *settingsPath != ""
+	if !explicit {
+		path = defaultSettingsPath()
+	}
+	settings, err := loadSettings(path, explicit)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	cache := pokecache.NewCache(time.Second * 10)
+	if disk, err := newDiskCache(); err == nil {
+		cache.SetDisk(disk)
+	} else {
+		fmt.Fprintln(os.Stderr, "disk cache disabled:", err)
+	}
+	client, err := newClient(cache, *offline, *record, *dataDir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	client.Timeout = *timeout
+	client.Limiter = api.NewLimiter(*rate)
+	if err := client.SetCachePolicies(settings.cachePolicies()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	format, err := output.ParseFormat(*outputFormat)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	config := cli.Config{
+		Next:       "",
+		Prev:       "",
+		Cache:      cache,
+		Client:     client,
+		Pokedex:    make(map[string]api.GetPokemon),
+		Seen:       make(map[string]int),
+		Collection: trainer.NewCollection(),
+		Out:        output.NewRenderer(format),
+	}
+	config.SetSeed(*seed)
+	if dir, err := os.UserConfigDir(); err == nil {
+		config.SaveDir = filepath.Join(dir, "pokedexcli", "saves")
+	}
+	if err := savepkg.Resume(&config); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+
+	s := &session{config: &config}
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go s.watch(signals)
+
+	s.exit(s.start(*oneShot, flag.Args(), os.Stdin))
+}
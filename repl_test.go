@@ -1,7 +1,15 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"testing"
+
+	"github.com/almasx/pokedexcli/internal/cli"
+	"github.com/almasx/pokedexcli/internal/output"
+	"github.com/almasx/pokedexcli/internal/registry"
 )
 
 func TestCleanInput(t *testing.T) {
@@ -29,4 +37,46 @@ func TestCleanInput(t *testing.T) {
             }
         }
     }
-}
\ No newline at end of file
+}
+func TestExecute(t *testing.T) {
+	cases := []struct {
+		input     string
+		expectErr bool
+	}{
+		{input: "", expectErr: false},
+		{input: "   # a comment", expectErr: false},
+		{input: "seed -5", expectErr: false},
+		{input: "seed 42", expectErr: false},
+		{input: "seed not-a-number", expectErr: true},
+		{input: "fly kanto", expectErr: true},
+	}
+
+	config := &cli.Config{Out: &output.Renderer{Format: output.Text, Out: io.Discard, Err: io.Discard}}
+	for _, c := range cases {
+		err := execute(context.Background(), config, c.input)
+		if (err != nil) != c.expectErr {
+			t.Errorf("execute(%q) returned %v, expected error: %v", c.input, err, c.expectErr)
+		}
+	}
+	if config.Seed != 42 {
+		t.Errorf("expected seed 42, got %v", config.Seed)
+	}
+}
+
+func TestExecuteInterrupted(t *testing.T) {
+	commands := registry.New()
+	commands.Register(&registry.Command{
+		Name: "test-wait",
+		Callback: func(ctx context.Context, config *cli.Config, args []string, flags map[string]string) error {
+			<-ctx.Done()
+			return fmt.Errorf("waiting: %w", ctx.Err())
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	config := &cli.Config{Out: &output.Renderer{Format: output.Text, Out: io.Discard, Err: io.Discard}}
+	if err := executeWith(ctx, commands, config, "test-wait"); !errors.Is(err, errInterrupted) {
+		t.Errorf("expected interrupted, got %v", err)
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/almasx/pokedexcli/internal/cli"
+	savepkg "github.com/almasx/pokedexcli/internal/save"
+)
+
+// shutdownHooks run, in order, whenever the session ends: exit, end of
+// input, a failed -c or run command, or a signal.
+var shutdownHooks = []func(*cli.Config) error{
+	finishRefreshes,
+	savepkg.Autosave,
+	closeInput,
+}
+
+// refreshTimeout bounds how long exiting waits for background refreshes.
+var refreshTimeout = 3 * time.Second
+
+// finishRefreshes lets stale entries served during the session finish
+// refreshing, so a -c or run invocation does not exit before they land.
+func finishRefreshes(config *cli.Config) error {
+	if config.Client == nil {
+		return nil
+	}
+	done := make(chan struct{})
+	go func() {
+		config.Client.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-time.After(refreshTimeout):
+		return fmt.Errorf("gave up on background cache refreshes after %v", refreshTimeout)
+	}
+}
+
+// closeInput saves the line editor's history and restores the terminal.
+func closeInput(config *cli.Config) error {
+	if closer, ok := config.Input.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// osExit ends the process; tests replace it to observe shutdown.
+var osExit = os.Exit
+
+// shutdown is the only way the session ends once it has started.
+func shutdown(config *cli.Config, code int) {
+	runShutdownHooks(config)
+	osExit(code)
+}
+
+func runShutdownHooks(config *cli.Config) {
+	for _, hook := range shutdownHooks {
+		if err := hook(config); err != nil {
+			fmt.Fprintln(os.Stderr, "shutdown:", err)
+		}
+	}
+}
+
+// session runs one command at a time and routes signals to it. SIGINT
+// cancels the command in flight and returns to the prompt; SIGTERM, or
+// SIGINT while waiting for input, ends the session through shutdown.
+type session struct {
+	config *cli.Config
+
+	mu sync.Mutex
+	// cancel stops the command in flight; it is nil between commands.
+	cancel   context.CancelFunc
+	stopping bool
+}
+
+// run executes one line with a context the next signal cancels.
+func (s *session) run(line string) error {
+	s.mu.Lock()
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.mu.Unlock()
+
+	err := execute(ctx, s.config, line)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cancel = nil
+	cancel()
+	if s.stopping {
+		shutdown(s.config, 128+int(syscall.SIGTERM))
+	}
+	return err
+}
+
+// watch handles signals until the channel is closed.
+func (s *session) watch(signals <-chan os.Signal) {
+	for sig := range signals {
+		s.mu.Lock()
+		if s.cancel != nil {
+			s.cancel()
+			s.stopping = s.stopping || sig == syscall.SIGTERM
+			s.mu.Unlock()
+			continue
+		}
+		// Nothing is running, so the main goroutine is only waiting for
+		// input and it is safe to save from here. The lock is held so no
+		// new command can start.
+		code := 128 + int(syscall.SIGINT)
+		if sig == syscall.SIGTERM {
+			code = 128 + int(syscall.SIGTERM)
+		}
+		shutdown(s.config, code)
+	}
+}
+
+// exit ends the session through shutdown. The lock is held so neither a
+// signal nor a new command can race with the save hooks.
+func (s *session) exit(code int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	shutdown(s.config, code)
+}
+
+// start runs the session in the mode picked on the command line: the -c
+// commands, a run script, or stdin. It returns the process exit code.
+func (s *session) start(oneShot string, args []string, stdin *os.File) int {
+	config := s.config
+	switch {
+	case oneShot != "":
+		for _, line := range strings.Split(oneShot, ";") {
+			if err := s.run(line); err != nil {
+				return 1
+			}
+		}
+	case len(args) > 0 && args[0] == "run":
+		if len(args) != 2 {
+			fmt.Fprintln(os.Stderr, "usage: pokedexcli run <script>")
+			return 2
+		}
+		script, err := os.Open(args[1])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		defer script.Close()
+		config.Input = cli.NewScannerInput(script, false)
+		if err := s.repl(true); err != nil {
+			return 1
+		}
+	default:
+		interactive := isTerminal(stdin)
+		if interactive {
+			config.Input = cli.NewEditor(historyPath(), func(line string) []string {
+				return commands.Complete(config, line)
+			})
+		} else {
+			config.Input = cli.NewScannerInput(stdin, false)
+		}
+		err := s.repl(!interactive)
+		if interactive {
+			config.Out.Println()
+			config.Out.Println(goodbye)
+		}
+		if err != nil {
+			return 1
+		}
+	}
+	return 0
+}
+
+// repl executes lines from config.Input until it is exhausted. In batch mode
+// it stops at the first command that fails.
+func (s *session) repl(batch bool) error {
+	for {
+		line, err := s.config.Input.ReadLine("Pokedex > ")
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := s.run(line); err != nil && batch {
+			return err
+		}
+	}
+}
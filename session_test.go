@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/almasx/pokedexcli/internal/api"
+	"github.com/almasx/pokedexcli/internal/apitest"
+	"github.com/almasx/pokedexcli/internal/cli"
+	"github.com/almasx/pokedexcli/internal/output"
+	savepkg "github.com/almasx/pokedexcli/internal/save"
+	"github.com/almasx/pokedexcli/internal/trainer"
+)
+
+// TestShutdownKeepsUnloadedSlot covers Ctrl-C or SIGTERM at the prompt of a
+// session whose save failed to load: the hooks must not overwrite it.
+func TestShutdownKeepsUnloadedSlot(t *testing.T) {
+	dir := t.TempDir()
+	saved := &cli.Config{SaveDir: dir, Collection: trainer.NewCollection()}
+	saved.Collection.Add(&trainer.Owned{Level: 5, Pokemon: api.GetPokemon{ID: 16, Name: "pidgey"}})
+	if err := savepkg.Save(saved, savepkg.DefaultSlot); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, savepkg.DefaultSlot+".json")
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runShutdownHooks(&cli.Config{SaveDir: dir, Collection: trainer.NewCollection()})
+	if after, _ := os.ReadFile(path); string(after) != string(before) {
+		t.Errorf("expected shutdown to leave the unloaded %v slot unchanged", savepkg.DefaultSlot)
+	}
+}
+
+// TestPipedInputSavesAtEOF covers stdin that is not a terminal: reaching the
+// end of it must run the save hooks like exit does.
+func TestPipedInputSavesAtEOF(t *testing.T) {
+	dir := t.TempDir()
+	stdin, err := os.CreateTemp(dir, "stdin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdin.Close()
+	if _, err := stdin.WriteString("seed 7\n"); err != nil {
+		t.Fatal(err)
+	}
+	stdin.Seek(0, 0)
+
+	exited := -1
+	defer func(previous func(int)) { osExit = previous }(osExit)
+	osExit = func(code int) { exited = code }
+
+	config := &cli.Config{
+		SaveDir:    dir,
+		SaveSlot:   savepkg.DefaultSlot,
+		Collection: trainer.NewCollection(),
+		Out:        &output.Renderer{Format: output.Text, Out: io.Discard, Err: io.Discard},
+	}
+	s := &session{config: config}
+	s.exit(s.start("", nil, stdin))
+	if exited != 0 {
+		t.Errorf("expected exit code 0, got %v", exited)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, savepkg.DefaultSlot+".json"))
+	if err != nil {
+		t.Fatalf("expected the session to be saved at EOF: %v", err)
+	}
+	if file, err := savepkg.Decode(data); err != nil || file.Seed != 7 {
+		t.Errorf("expected the saved seed to be 7, got %+v, %v", file.Seed, err)
+	}
+}
+
+func TestInterruptKeepsPendingTerminate(t *testing.T) {
+	s := &session{config: &cli.Config{}, cancel: func() {}}
+	signals := make(chan os.Signal, 2)
+	signals <- syscall.SIGTERM
+	signals <- syscall.SIGINT
+	close(signals)
+	s.watch(signals)
+	if !s.stopping {
+		t.Errorf("expected a SIGINT after SIGTERM to keep the session stopping")
+	}
+}
+
+// TestExitSavesFirstSession covers a first session that catches a pokemon and
+// exits without ever running save.
+func TestExitSavesFirstSession(t *testing.T) {
+	client := apitest.NewClient(t, apitest.Serve(map[string]string{
+		"/pokemon/pikachu":         `{"id": 25, "name": "pikachu", "species": {"name": "pikachu"}}`,
+		"/pokemon-species/pikachu": `{"id": 25, "name": "pikachu", "capture_rate": 190}`,
+	}))
+
+	defer func(previous func(int)) { osExit = previous }(osExit)
+	osExit = func(int) {}
+
+	dir := t.TempDir()
+	config := &cli.Config{
+		SaveDir:    dir,
+		Client:     client,
+		Pokedex:    map[string]api.GetPokemon{},
+		Collection: trainer.NewCollection(),
+		Out:        &output.Renderer{Format: output.Text, Out: io.Discard, Err: io.Discard},
+	}
+	config.SetSeed(1)
+	if err := savepkg.Resume(config); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"catch pikachu --ball master", "exit"} {
+		if err := execute(context.Background(), config, line); err != nil {
+			t.Fatalf("%v: %v", line, err)
+		}
+	}
+
+	resumed := &cli.Config{SaveDir: dir}
+	if err := savepkg.Resume(resumed); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := resumed.Pokedex["pikachu"]; !ok {
+		t.Errorf("expected the caught pikachu to be saved on exit, got %v", resumed.Pokedex)
+	}
+}
+
+func TestShutdownFinishesRefreshes(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	client := apitest.NewClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) > 1 {
+			<-release
+		}
+		fmt.Fprint(w, requests.Load())
+	})
+	client.SetCachePolicies([]api.CachePolicy{{Pattern: "pokemon/*", Stale: time.Hour}})
+	config := &cli.Config{Client: client}
+
+	url := client.URL("pokemon/pikachu")
+	client.Fetch(context.Background(), url)
+	time.Sleep(time.Millisecond)
+	if body, _ := client.Fetch(context.Background(), url); string(body) != "1" {
+		t.Fatalf("expected the stale entry to be served, got %q", body)
+	}
+
+	defer func(previous time.Duration) { refreshTimeout = previous }(refreshTimeout)
+	refreshTimeout = 10 * time.Millisecond
+	if err := finishRefreshes(config); err == nil {
+		t.Errorf("expected a hung refresh to be given up on")
+	}
+
+	close(release)
+	refreshTimeout = time.Minute
+	if err := finishRefreshes(config); err != nil {
+		t.Fatal(err)
+	}
+	if entry, _ := client.Cache.Lookup(url); string(entry.Val) != "2" {
+		t.Errorf("expected the refresh to have landed before exit, got %q", entry.Val)
+	}
+}
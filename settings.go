@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/almasx/pokedexcli/internal/api"
+)
+
+// settings is the optional JSON config file, e.g.
+//
+//	{
+//	  "cache": {
+//	    "policies": [
+//	      {"pattern": "pokemon/*", "ttl": "30d", "stale": "7d"},
+//	      {"pattern": "location-area?offset=*", "ttl": "1d"}
+//	    ]
+//	  }
+//	}
+type settings struct {
+	Cache struct {
+		// Policies are checked before api.DefaultCachePolicies.
+		Policies []api.CachePolicy `json:"policies"`
+	} `json:"cache"`
+}
+
+// defaultSettingsPath is where the config file lives unless -config says
+// otherwise, or "" when there is no config directory.
+func defaultSettingsPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "pokedexcli", "config.json")
+}
+
+// loadSettings reads the config file at path. A missing file is only an
+// error when the user named it explicitly.
+func loadSettings(path string, explicit bool) (settings, error) {
+	var s settings
+	if path == "" {
+		return s, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&s); err != nil {
+		return s, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+// cachePolicies is the configured policies followed by the defaults.
+func (s settings) cachePolicies() []api.CachePolicy {
+	return append(append([]api.CachePolicy{}, s.Cache.Policies...), api.DefaultCachePolicies...)
+}